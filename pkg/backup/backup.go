@@ -11,6 +11,7 @@ import (
 	"github.com/orbatschow/kontext/pkg/kubeconfig"
 	"github.com/orbatschow/kontext/pkg/logger"
 	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
 )
 
 type Filename string
@@ -47,6 +48,7 @@ func (r *Reconciler) Reconcile() error {
 		return err
 	}
 	r.State.Backup.Revisions = revisions
+	r.State.Backup.Snapshots = r.computeSnapshots(state.Revision(backupFile.Name()))
 
 	err = state.Write(r.Config, r.State)
 	if err != nil {
@@ -92,6 +94,27 @@ func (r *Reconciler) create() (*os.File, error) {
 	return backupFile, nil
 }
 
+// computeSnapshots records the active group and context for the given revision and
+// removes all snapshots, that belong to revisions, that do not exist anymore
+func (r *Reconciler) computeSnapshots(revision state.Revision) map[state.Revision]state.Snapshot {
+	snapshots := map[state.Revision]state.Snapshot{}
+
+	for _, item := range r.State.Backup.Revisions {
+		if snapshot, ok := r.State.Backup.Snapshots[item]; ok {
+			snapshots[item] = snapshot
+		}
+	}
+
+	if lo.Contains(r.State.Backup.Revisions, revision) {
+		snapshots[revision] = state.Snapshot{
+			Group:   r.State.Group.Active,
+			Context: r.State.Context.Active,
+		}
+	}
+
+	return snapshots
+}
+
 // computeBackupFileName builds the file name for the new backup
 func computeBackupFileName(config *config.Config) Filename {
 	// compute the current timestamp
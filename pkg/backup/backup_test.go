@@ -89,12 +89,17 @@ func Test_Reconcile(t *testing.T) {
 					t.Errorf("%v", err)
 				}
 
+				revisions := lo.Map(files, func(item os.DirEntry, index int) state.Revision {
+					return state.Revision(filepath.Join(filepath.Join(tempDirectory, "backup"), item.Name()))
+				})
+
 				return &state.State{
 					Group:   state.Group{},
 					Context: state.Context{},
 					Backup: state.Backup{
-						Revisions: lo.Map(files, func(item os.DirEntry, index int) state.Revision {
-							return state.Revision(filepath.Join(filepath.Join(tempDirectory, "backup"), item.Name()))
+						Revisions: revisions,
+						Snapshots: lo.SliceToMap(revisions, func(item state.Revision) (state.Revision, state.Snapshot) {
+							return item, state.Snapshot{}
 						}),
 					},
 				}
@@ -0,0 +1,246 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	MaxSelectHeight = 500
+	// RelativeRevisionPrefix marks a revision, that is addressed relative to the latest one, e.g. -1
+	RelativeRevisionPrefix = "-"
+)
+
+var revisionPattern = regexp.MustCompile(`kubeconfig-(\d+)\.yaml$`)
+
+type Client struct {
+	Config    *config.Config
+	State     *state.State
+	APIConfig *api.Config
+}
+
+// Entry describes a single backup revision
+type Entry struct {
+	Revision  state.Revision
+	ID        string
+	Timestamp time.Time
+	Size      int64
+	Group     string
+	Context   string
+}
+
+func New(configFile string) (*Client, error) {
+	configClient := &config.Client{
+		File: configFile,
+	}
+	config, err := configClient.Read()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(config.Global.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := state.Read(config)
+	if err != nil {
+		return nil, err
+	}
+
+	apiConfig, err := kubeconfig.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		Config:    config,
+		State:     state,
+		APIConfig: apiConfig,
+	}, nil
+}
+
+// List returns all backup revisions, that are tracked within the state, the latest revision comes first
+func (c *Client) List() []Entry {
+	log := logger.New()
+	var buffer []Entry
+
+	for _, revision := range c.State.Backup.Revisions {
+		info, err := os.Stat(string(revision))
+		if err != nil {
+			log.Warn("skipping backup revision, it is not readable", log.Args("revision", revision, "err", err))
+			continue
+		}
+
+		snapshot := c.State.Backup.Snapshots[revision]
+		id, timestamp := parseRevision(revision, info)
+
+		buffer = append(buffer, Entry{
+			Revision:  revision,
+			ID:        id,
+			Timestamp: timestamp,
+			Size:      info.Size(),
+			Group:     snapshot.Group,
+			Context:   snapshot.Context,
+		})
+	}
+
+	return lo.Reverse(buffer)
+}
+
+// Get finds a backup revision by its id, file name or path
+// an identifier like -N refers to the N-th latest revision, -1 being the latest one
+func (c *Client) Get(identifier string) (*Entry, error) {
+	if len(identifier) == 0 {
+		return nil, fmt.Errorf("given revision is empty")
+	}
+
+	entries := c.List()
+
+	if strings.HasPrefix(identifier, RelativeRevisionPrefix) {
+		offset, err := strconv.Atoi(strings.TrimPrefix(identifier, RelativeRevisionPrefix))
+		if err != nil || offset < 1 {
+			return nil, fmt.Errorf("invalid relative revision: '%s'", identifier)
+		}
+		if offset > len(entries) {
+			return nil, fmt.Errorf("could not find revision '%s', only %d revision(s) available", identifier, len(entries))
+		}
+		return &entries[offset-1], nil
+	}
+
+	match, ok := lo.Find(entries, func(item Entry) bool {
+		return item.ID == identifier ||
+			string(item.Revision) == identifier ||
+			filepath.Base(string(item.Revision)) == identifier
+	})
+	if !ok {
+		return nil, fmt.Errorf("could not find revision: '%s'", identifier)
+	}
+
+	return &match, nil
+}
+
+// Restore replaces the api config with the given backup revision and updates the state accordingly
+// Invoking this method without an identifier will spawn an interactive selection dialog.
+// The current kubeconfig is backed up before, so a restore can be reverted as well.
+func (c *Client) Restore(identifier string) error {
+	log := logger.New()
+
+	if len(identifier) == 0 {
+		selection, err := c.Select()
+		if err != nil {
+			return err
+		}
+		identifier = selection
+	}
+
+	entry, err := c.Get(identifier)
+	if err != nil {
+		return err
+	}
+
+	// read the revision before creating a new backup, as the revision might be removed by the backup rotation
+	apiConfig, err := read(entry)
+	if err != nil {
+		return err
+	}
+
+	reconciler := Reconciler{
+		Config: c.Config,
+		State:  c.State,
+	}
+	err = reconciler.Reconcile()
+	if err != nil {
+		return err
+	}
+
+	c.APIConfig = apiConfig
+	if len(entry.Group) > 0 {
+		c.State.Group.Active = entry.Group
+		c.State.Group.History = state.ComputeHistory(c.Config, state.History(entry.Group), c.State.Group.History)
+	}
+	if len(apiConfig.CurrentContext) > 0 {
+		c.State.Context.Active = apiConfig.CurrentContext
+		c.State.Context.History = state.ComputeHistory(c.Config, state.History(apiConfig.CurrentContext), c.State.Context.History)
+	}
+
+	log.Info("restored backup revision", log.Args("revision", entry.ID, "group", entry.Group, "context", apiConfig.CurrentContext))
+	return nil
+}
+
+// Diff computes the changes between two backup revisions, an empty target refers to the current kubeconfig
+func (c *Client) Diff(source string, target string) ([]kubeconfig.Change, error) {
+	sourceEntry, err := c.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	sourceConfig, err := read(sourceEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	targetConfig := c.APIConfig
+	if len(target) > 0 {
+		targetEntry, err := c.Get(target)
+		if err != nil {
+			return nil, err
+		}
+		targetConfig, err = read(targetEntry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return kubeconfig.Diff(sourceConfig, targetConfig), nil
+}
+
+// Select spawns an interactive selection dialog and returns the id of the selected revision
+func (c *Client) Select() (string, error) {
+	printer, options, err := c.buildInteractiveSelectPrinter()
+	if err != nil {
+		return "", err
+	}
+	selection, err := printer.Show()
+	if err != nil {
+		return "", err
+	}
+	return options[selection], nil
+}
+
+// read loads the kubeconfig of the given backup revision
+func read(entry *Entry) (*api.Config, error) {
+	file, err := os.Open(string(entry.Revision))
+	if err != nil {
+		return nil, fmt.Errorf("could not open backup revision, err: '%w'", err)
+	}
+	defer file.Close()
+
+	return kubeconfig.Read(file)
+}
+
+// parseRevision computes the id and the creation time of a revision, the timestamp is taken from
+// the file name and falls back to the modification time of the file
+func parseRevision(revision state.Revision, info os.FileInfo) (string, time.Time) {
+	match := revisionPattern.FindStringSubmatch(string(revision))
+	if match == nil {
+		return filepath.Base(string(revision)), info.ModTime()
+	}
+
+	milliseconds, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return match[1], info.ModTime()
+	}
+
+	return match[1], time.UnixMilli(milliseconds)
+}
@@ -0,0 +1,361 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// generateRevisions copies the test kubeconfig into the given directory, once per timestamp
+func generateRevisions(t *testing.T, directory string, timestamps ...int64) []state.Revision {
+	_, caller, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var buffer []state.Revision
+	for _, timestamp := range timestamps {
+		path := filepath.Join(directory, "kubeconfig-"+strconv.FormatInt(timestamp, 10)+".yaml")
+		err = os.WriteFile(path, data, 0600)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		buffer = append(buffer, state.Revision(path))
+	}
+	return buffer
+}
+
+func Test_List(t *testing.T) {
+	directory := t.TempDir()
+	revisions := generateRevisions(t, directory, 1681000000000, 1681000001000)
+	info, err := os.Stat(string(revisions[0]))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := []struct {
+		name  string
+		state *state.State
+		want  []Entry
+	}{
+		{
+			name: "should list all revisions, the latest revision first",
+			state: &state.State{
+				Backup: state.Backup{
+					Revisions: revisions,
+					Snapshots: map[state.Revision]state.Snapshot{
+						revisions[1]: {
+							Group:   "dev",
+							Context: "kind-kontext",
+						},
+					},
+				},
+			},
+			want: []Entry{
+				{
+					Revision:  revisions[1],
+					ID:        "1681000001000",
+					Timestamp: time.UnixMilli(1681000001000),
+					Size:      info.Size(),
+					Group:     "dev",
+					Context:   "kind-kontext",
+				},
+				{
+					Revision:  revisions[0],
+					ID:        "1681000000000",
+					Timestamp: time.UnixMilli(1681000000000),
+					Size:      info.Size(),
+				},
+			},
+		},
+		{
+			name: "should skip revisions, that do not exist anymore",
+			state: &state.State{
+				Backup: state.Backup{
+					Revisions: []state.Revision{
+						revisions[0],
+						state.Revision(filepath.Join(directory, "kubeconfig-1.yaml")),
+					},
+				},
+			},
+			want: []Entry{
+				{
+					Revision:  revisions[0],
+					ID:        "1681000000000",
+					Timestamp: time.UnixMilli(1681000000000),
+					Size:      info.Size(),
+				},
+			},
+		},
+		{
+			name:  "should return nil, as there are no revisions",
+			state: &state.State{},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				State: tt.state,
+			}
+
+			got := client.List()
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("backup.List() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Get(t *testing.T) {
+	directory := t.TempDir()
+	revisions := generateRevisions(t, directory, 1681000000000, 1681000001000, 1681000002000)
+
+	tests := []struct {
+		name       string
+		identifier string
+		want       state.Revision
+		wantErr    bool
+	}{
+		{
+			name:       "should find the revision by its id",
+			identifier: "1681000001000",
+			want:       revisions[1],
+		},
+		{
+			name:       "should find the revision by its file name",
+			identifier: "kubeconfig-1681000000000.yaml",
+			want:       revisions[0],
+		},
+		{
+			name:       "should find the revision by its path",
+			identifier: string(revisions[2]),
+			want:       revisions[2],
+		},
+		{
+			name:       "should find the latest revision",
+			identifier: "-1",
+			want:       revisions[2],
+		},
+		{
+			name:       "should find the oldest revision relative to the latest one",
+			identifier: "-3",
+			want:       revisions[0],
+		},
+		{
+			name:       "should throw an error, as the relative revision exceeds the available revisions",
+			identifier: "-4",
+			wantErr:    true,
+		},
+		{
+			name:       "should throw an error, as the relative revision is invalid",
+			identifier: "-a",
+			wantErr:    true,
+		},
+		{
+			name:       "should throw an error, as the revision does not exist",
+			identifier: "1",
+			wantErr:    true,
+		},
+		{
+			name:       "should throw an error, as the identifier is empty",
+			identifier: "",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				State: &state.State{
+					Backup: state.Backup{
+						Revisions: revisions,
+					},
+				},
+			}
+
+			got, err := client.Get(tt.identifier)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && tt.want != got.Revision {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got.Revision)
+			}
+		})
+	}
+}
+
+func Test_Restore(t *testing.T) {
+	directory := t.TempDir()
+	backupDirectory := filepath.Join(directory, "backup")
+	err := os.Mkdir(backupDirectory, 0700)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	revisions := generateRevisions(t, backupDirectory, 1681000000000)
+
+	// the current kubeconfig, that will be replaced by the revision
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+
+	client := Client{
+		Config: &config.Config{
+			Global: config.Global{
+				Kubeconfig: kubeconfigFile,
+			},
+			State: config.State{
+				File: filepath.Join(directory, "state.json"),
+				History: config.History{
+					Size: state.DefaultMaximumHistorySize,
+				},
+			},
+			Backup: config.Backup{
+				Enabled:   true,
+				Directory: backupDirectory,
+				Revisions: config.DefaultBackupRevisionLimit,
+			},
+		},
+		State: &state.State{
+			Group: state.Group{
+				Active: "prod",
+			},
+			Context: state.Context{
+				Active: "kind-prod",
+			},
+			Backup: state.Backup{
+				Revisions: revisions,
+				Snapshots: map[state.Revision]state.Snapshot{
+					revisions[0]: {
+						Group:   "dev",
+						Context: "kind-kontext",
+					},
+				},
+			},
+		},
+	}
+
+	err = client.Restore("1681000000000")
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	if client.APIConfig.CurrentContext != "kind-kontext" {
+		t.Errorf("want: '%s', got: '%s'", "kind-kontext", client.APIConfig.CurrentContext)
+	}
+
+	want := state.Group{
+		Active:  "dev",
+		History: []state.History{"dev"},
+	}
+	if !cmp.Equal(want, client.State.Group) {
+		diff := cmp.Diff(want, client.State.Group)
+		t.Errorf("backup.Restore() group mismatch (-want +got):\n%s", diff)
+	}
+
+	if client.State.Context.Active != "kind-kontext" {
+		t.Errorf("want: '%s', got: '%s'", "kind-kontext", client.State.Context.Active)
+	}
+
+	// the restore has to create a new revision, that captures the state before the restore
+	if len(client.State.Backup.Revisions) != 2 {
+		t.Fatalf("want two revisions, got: '%v'", client.State.Backup.Revisions)
+	}
+	latest := lo.Must(lo.Last(client.State.Backup.Revisions))
+	wantSnapshot := state.Snapshot{
+		Group:   "prod",
+		Context: "kind-prod",
+	}
+	if !cmp.Equal(wantSnapshot, client.State.Backup.Snapshots[latest]) {
+		diff := cmp.Diff(wantSnapshot, client.State.Backup.Snapshots[latest])
+		t.Errorf("backup.Restore() snapshot mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_Diff(t *testing.T) {
+	directory := t.TempDir()
+	revisions := generateRevisions(t, directory, 1681000000000, 1681000001000)
+
+	tests := []struct {
+		name      string
+		source    string
+		target    string
+		apiConfig *api.Config
+		want      []kubeconfig.Change
+		wantErr   bool
+	}{
+		{
+			name:   "should not report any changes between two equal revisions",
+			source: "-2",
+			target: "-1",
+			want:   nil,
+		},
+		{
+			name:   "should report the changes between a revision and the current kubeconfig",
+			source: "-1",
+			apiConfig: func() *api.Config {
+				file, err := os.Open(string(revisions[0]))
+				if err != nil {
+					t.Fatalf("%v", err)
+				}
+				apiConfig, err := kubeconfig.Read(file)
+				if err != nil {
+					t.Fatalf("%v", err)
+				}
+				apiConfig.CurrentContext = "kind-prod"
+				delete(apiConfig.Clusters, "kind-kontext")
+				return apiConfig
+			}(),
+			want: []kubeconfig.Change{
+				{Kind: kubeconfig.KindCurrentContext, Operation: kubeconfig.OperationModified, From: "kind-kontext", To: "kind-prod"},
+				{Kind: kubeconfig.KindCluster, Name: "kind-kontext", Operation: kubeconfig.OperationRemoved},
+			},
+		},
+		{
+			name:    "should throw an error, as the revision does not exist",
+			source:  "-3",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				State: &state.State{
+					Backup: state.Backup{
+						Revisions: revisions,
+					},
+				},
+				APIConfig: tt.apiConfig,
+			}
+
+			got, err := client.Diff(tt.source, tt.target)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("backup.Diff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
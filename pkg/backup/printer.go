@@ -0,0 +1,37 @@
+package backup
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/pterm/pterm"
+)
+
+func (c *Client) BuildTablePrinter(entries ...Entry) *pterm.TablePrinter {
+	table := pterm.TableData{
+		{"Revision", "Created", "Size", "Group", "Context"},
+	}
+
+	for _, entry := range entries {
+		table = append(table, []string{
+			entry.ID, entry.Timestamp.Format(time.DateTime), strconv.FormatInt(entry.Size, 10), entry.Group, entry.Context,
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithData(table)
+}
+
+func (c *Client) BuildDiffTablePrinter(changes ...kubeconfig.Change) *pterm.TablePrinter {
+	table := pterm.TableData{
+		{"Operation", "Kind", "Name", "Field", "From", "To"},
+	}
+
+	for _, change := range changes {
+		table = append(table, []string{
+			string(change.Operation), string(change.Kind), change.Name, change.Field, change.From, change.To,
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithData(table)
+}
@@ -0,0 +1,31 @@
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// start an interactive revision selection, the returned map resolves each option to its revision id
+func (c *Client) buildInteractiveSelectPrinter() (*pterm.InteractiveSelectPrinter, map[string]string, error) {
+	entries := c.List()
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("could not find any backup revisions")
+	}
+
+	var keys []string
+	options := map[string]string{}
+
+	for _, entry := range entries {
+		key := fmt.Sprintf("%s | %s | %s/%s", entry.ID, entry.Timestamp.Format(time.DateTime), entry.Group, entry.Context)
+		keys = append(keys, key)
+		options[key] = entry.ID
+	}
+
+	selector := pterm.DefaultInteractiveSelect.
+		WithMaxHeight(MaxSelectHeight).
+		WithOptions(keys)
+
+	return selector, options, nil
+}
@@ -0,0 +1,182 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/orbatschow/kontext/pkg/backup"
+	"github.com/orbatschow/kontext/pkg/cmd/get"
+	"github.com/orbatschow/kontext/pkg/cmd/set"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/spf13/cobra"
+)
+
+// relativeRevisionFlagPattern matches the error of pflag, that reads a relative revision as shorthand flag, e.g. -1
+var relativeRevisionFlagPattern = regexp.MustCompile(`unknown shorthand flag: '\d'`)
+
+// relativeRevisionError explains, that relative revisions have to follow the end of the flags
+func relativeRevisionError(cmd *cobra.Command, err error) error {
+	if relativeRevisionFlagPattern.MatchString(err.Error()) {
+		return fmt.Errorf("%w, relative revisions have to follow '--', e.g. '%s -- -1'", err, cmd.CommandPath())
+	}
+	return err
+}
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "list",
+		Short:  "list all backup revisions, the latest revision comes first",
+		PreRun: get.Init,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			client, err := backup.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			printer := client.BuildTablePrinter(client.List()...)
+			err = printer.Render()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	return cmd
+}
+
+func newRestoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [revision|-- -N]",
+		Short: "restore the kubeconfig from a backup revision",
+		Long: `Invoking this command without a parameter will spawn an interactive selection dialog.
+A revision can be referenced by its id, file name or path.
+'-N' refers to the N-th latest revision and has to follow '--', e.g. '-- -1' restores the latest revision.
+The current kubeconfig will be backed up before the restore is performed.
+		`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  set.Lock,
+		PostRun: set.Release,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+			var revision string
+			if len(args) > 0 {
+				revision = args[0]
+			}
+
+			client, err := backup.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			err = client.Restore(revision)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			err = state.Write(client.Config, client.State)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.SetFlagErrorFunc(relativeRevisionError)
+	return cmd
+}
+
+func newDiffCommand() *cobra.Command {
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "diff [revision] [revision] | -- -N [-N]",
+		Short: "show the changes between a backup revision and the current kubeconfig or another revision",
+		Long: `Invoking this command without a parameter will spawn an interactive selection dialog.
+Given one revision, the changes from this revision to the current kubeconfig are shown.
+Given two revisions, the changes from the first to the second revision are shown.
+A revision can be referenced by its id, file name or path, '-N' refers to the N-th latest revision and has to
+follow '--', e.g. '-- -2 -1' compares the second latest with the latest revision.
+Credentials are redacted, unless --show-secrets is set.
+		`,
+		Args:   cobra.MaximumNArgs(2),
+		PreRun: get.Init,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+			var source, target string
+			if len(args) > 0 {
+				source = args[0]
+			}
+			if len(args) > 1 {
+				target = args[1]
+			}
+
+			client, err := backup.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			if len(source) == 0 {
+				source, err = client.Select()
+				if err != nil {
+					log.Error(err.Error())
+					os.Exit(1)
+				}
+			}
+
+			changes, err := client.Diff(source, target)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			if len(changes) == 0 {
+				log.Info("no changes")
+				return
+			}
+			if !showSecrets {
+				changes = kubeconfig.Redact(changes)
+			}
+
+			printer := client.BuildDiffTablePrinter(changes...)
+			err = printer.Render()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "show credentials instead of redacting them")
+	cmd.SetFlagErrorFunc(relativeRevisionError)
+	return cmd
+}
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "list, compare and restore backup revisions",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(newDiffCommand())
+	cmd.AddCommand(newListCommand())
+	cmd.AddCommand(newRestoreCommand())
+	return cmd
+}
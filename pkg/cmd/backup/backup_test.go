@@ -0,0 +1,226 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// generateConfig creates a config file, the current kubeconfig and one backup revision per context,
+// the last context refers to the latest revision
+func generateConfig(t *testing.T, contextNames ...string) (string, string) {
+	directory := t.TempDir()
+	configFile := filepath.Join(directory, "kontext.yaml")
+	kubeconfigFile := filepath.Join(directory, "kubeconfig.yaml")
+	backupDirectory := filepath.Join(directory, "backup")
+
+	err := os.Mkdir(backupDirectory, 0700)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	data := fmt.Sprintf("global:\n  kubeconfig: %s\nstate:\n  file: %s\nbackup:\n  enabled: true\n  directory: %s\n",
+		kubeconfigFile, filepath.Join(directory, "state.json"), backupDirectory)
+	err = os.WriteFile(configFile, []byte(data), 0600)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	err = kubeconfig.WriteFile(kubeconfigFile, generateKubeconfig("kind-current"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	currentState := &state.State{}
+	for i, contextName := range contextNames {
+		revision := state.Revision(filepath.Join(backupDirectory, fmt.Sprintf("kubeconfig-%d.yaml", 1681000000000+i*1000)))
+		err = kubeconfig.WriteFile(string(revision), generateKubeconfig(contextName))
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		currentState.Backup.Revisions = append(currentState.Backup.Revisions, revision)
+	}
+
+	configClient := &config.Client{
+		File: configFile,
+	}
+	currentConfig, err := configClient.Read()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = state.Write(currentConfig, currentState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return configFile, kubeconfigFile
+}
+
+func generateKubeconfig(contextName string) *api.Config {
+	return &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"kind": {Server: "https://127.0.0.1:6443"},
+		},
+		AuthInfos: map[string]*api.AuthInfo{
+			"kind": {Token: "token"},
+		},
+		Contexts: map[string]*api.Context{
+			contextName: {Cluster: "kind", AuthInfo: "kind"},
+		},
+		CurrentContext: contextName,
+	}
+}
+
+// execute runs the backup command below a root command, that provides the persistent config flag
+func execute(t *testing.T, args ...string) (*cobra.Command, error) {
+	t.Cleanup(func() {
+		config.File = ""
+	})
+
+	rootCmd := &cobra.Command{Use: "kontext"}
+	rootCmd.PersistentFlags().StringVarP(&config.File, "config", "c", "", "config file")
+	rootCmd.AddCommand(NewCommand())
+	rootCmd.SetArgs(args)
+
+	return rootCmd.ExecuteC()
+}
+
+func Test_RestoreCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{
+			name:     "should restore the latest revision",
+			args:     []string{"--", "-1"},
+			want:     "kind-new",
+			wantArgs: []string{"-1"},
+		},
+		{
+			name:     "should restore the second latest revision",
+			args:     []string{"--", "-2"},
+			want:     "kind-old",
+			wantArgs: []string{"-2"},
+		},
+		{
+			name:    "should throw an error, as the relative revision does not follow the end of the flags",
+			args:    []string{"-1"},
+			want:    "kind-current",
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as only one revision is allowed",
+			args:    []string{"--", "-2", "-1"},
+			want:    "kind-current",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile, kubeconfigFile := generateConfig(t, "kind-old", "kind-new")
+
+			cmd, err := execute(t, append([]string{"backup", "restore", "--config", configFile}, tt.args...)...)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.wantArgs, cmd.Flags().Args()) {
+				diff := cmp.Diff(tt.wantArgs, cmd.Flags().Args())
+				t.Errorf("backup restore arguments mismatch (-want +got):\n%s", diff)
+			}
+
+			file, err := os.Open(kubeconfigFile)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			defer file.Close()
+			got, err := kubeconfig.Read(file)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if tt.want != got.CurrentContext {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got.CurrentContext)
+			}
+		})
+	}
+}
+
+func Test_DiffCommand(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		wantArgs        []string
+		wantShowSecrets bool
+		wantErr         bool
+	}{
+		{
+			name:     "should compare the latest revision with the current kubeconfig",
+			args:     []string{"--", "-1"},
+			wantArgs: []string{"-1"},
+		},
+		{
+			name:            "should compare two relative revisions and keep their order",
+			args:            []string{"--show-secrets", "--", "-2", "-1"},
+			wantArgs:        []string{"-2", "-1"},
+			wantShowSecrets: true,
+		},
+		{
+			name:    "should throw an error, as the relative revision does not follow the end of the flags",
+			args:    []string{"-1"},
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the flag is unknown",
+			args:    []string{"-x", "--", "-1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile, _ := generateConfig(t, "kind-old", "kind-new")
+
+			cmd, err := execute(t, append([]string{"backup", "diff", "--config", configFile}, tt.args...)...)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			if !cmp.Equal(tt.wantArgs, cmd.Flags().Args()) {
+				diff := cmp.Diff(tt.wantArgs, cmd.Flags().Args())
+				t.Errorf("backup diff arguments mismatch (-want +got):\n%s", diff)
+			}
+			showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+			if tt.wantShowSecrets != showSecrets {
+				t.Errorf("want: '%t', got: '%t'", tt.wantShowSecrets, showSecrets)
+			}
+		})
+	}
+}
+
+func Test_relativeRevisionError(t *testing.T) {
+	_, err := execute(t, "backup", "restore", "-1")
+	if err == nil || !strings.Contains(err.Error(), "'kontext backup restore -- -1'") {
+		t.Errorf("want a hint for relative revisions, got: '%v'", err)
+	}
+}
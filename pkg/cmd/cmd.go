@@ -3,19 +3,56 @@ package cmd
 import (
 	"os"
 
+	"github.com/orbatschow/kontext/pkg/cmd/backup"
+	cmdconfig "github.com/orbatschow/kontext/pkg/cmd/config"
+	"github.com/orbatschow/kontext/pkg/cmd/exec"
+	"github.com/orbatschow/kontext/pkg/cmd/export"
+	"github.com/orbatschow/kontext/pkg/cmd/foreach"
+	"github.com/orbatschow/kontext/pkg/cmd/gc"
 	"github.com/orbatschow/kontext/pkg/cmd/get"
+	"github.com/orbatschow/kontext/pkg/cmd/importer"
+	cmdlabel "github.com/orbatschow/kontext/pkg/cmd/label"
 	"github.com/orbatschow/kontext/pkg/cmd/reload"
+	"github.com/orbatschow/kontext/pkg/cmd/session"
 	"github.com/orbatschow/kontext/pkg/cmd/set"
+	"github.com/orbatschow/kontext/pkg/cmd/shell"
+	"github.com/orbatschow/kontext/pkg/cmd/split"
 	"github.com/orbatschow/kontext/pkg/cmd/version"
+	"github.com/orbatschow/kontext/pkg/cmd/watch"
+	"github.com/orbatschow/kontext/pkg/config"
 	"github.com/orbatschow/kontext/pkg/logger"
 	"github.com/pterm/pterm"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
+// skipExpire contains all commands, that must not revert expired protected contexts
+var skipExpire = []string{"gc", "version", "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
 var rootCmd = &cobra.Command{
-	Use:    "kontext",
-	Short:  "manage kubernetes config files, contexts, groups and sources",
-	PreRun: set.Init,
+	Use:   "kontext",
+	Short: "manage kubernetes config files, contexts, groups and sources",
+	// resolve the config file once, all commands pass it to their clients
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		log := logger.New()
+
+		file, err := config.Resolve(config.File)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		config.File = file
+		log.Debug("resolved config file", log.Args("file", config.File))
+
+		// revert expired protected contexts, before the command sees them
+		// The previews of fzf are skipped, the selecting kontext process holds the lock.
+		_, preview := os.LookupEnv(config.PreviewEnvironmentVariable)
+		if !preview && !lo.Contains(skipExpire, cmd.Name()) && (!cmd.HasParent() || cmd.Parent().Name() != "completion") {
+			gc.Expire(cmd, args)
+		}
+	},
+	PreRun:  set.Init,
+	PostRun: set.Release,
 	Run: func(cmd *cobra.Command, args []string) {
 		set.NewSetContextCommand(cmd, args)
 	},
@@ -23,12 +60,26 @@ var rootCmd = &cobra.Command{
 
 func Execute() {
 	// add commands
+	rootCmd.AddCommand(backup.NewCommand())
+	rootCmd.AddCommand(cmdconfig.NewCommand())
+	rootCmd.AddCommand(exec.NewCommand())
+	rootCmd.AddCommand(export.NewCommand())
+	rootCmd.AddCommand(foreach.NewCommand())
+	rootCmd.AddCommand(gc.NewCommand())
 	rootCmd.AddCommand(get.NewCommand())
+	rootCmd.AddCommand(importer.NewCommand())
+	rootCmd.AddCommand(cmdlabel.NewCommand())
 	rootCmd.AddCommand(set.NewCommand())
 	rootCmd.AddCommand(reload.NewCommand())
+	rootCmd.AddCommand(session.NewCommand())
+	rootCmd.AddCommand(shell.NewCommand())
+	rootCmd.AddCommand(split.NewCommand())
 	rootCmd.AddCommand(version.NewCommand())
+	rootCmd.AddCommand(watch.NewCommand())
 
+	set.AddYesFlag(rootCmd)
 	rootCmd.PersistentFlags().IntVarP(&logger.Verbosity, "verbosity", "v", logger.DefaultVerbosity, "verbose output")
+	rootCmd.PersistentFlags().StringVarP(&config.File, "config", "c", "", "config file, defaults to $"+config.ConfigEnvironmentVariable+", "+config.ProjectConfigFile+" or "+config.DefaultConfigPath)
 
 	if err := rootCmd.Execute(); err != nil {
 		pterm.Printfln("%v", err)
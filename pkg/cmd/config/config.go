@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/group"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "validate the config file",
+		Long: `Checks the config file for unknown keys, duplicate names, undefined sources and groups,
+invalid sort values, invalid glob patterns, default contexts, that are not provided by the sources of their group,
+and clusters, users and contexts, whose names are defined by more than one file of a group.
+Each issue is reported with its location within the config file, the command fails if there is at least one issue.
+		`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			configClient := &config.Client{
+				File: config.File,
+			}
+			currentConfig, err := configClient.Load()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			issues, err := configClient.Validate(currentConfig, group.ValidateContexts, group.ValidateConflicts)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			if len(issues) == 0 {
+				log.Info("config is valid", log.Args("file", configClient.File))
+				return
+			}
+			for _, issue := range issues {
+				pterm.Println(issue.String())
+			}
+			log.Error("config is invalid", log.Args("file", configClient.File, "issues", len(issues)))
+			os.Exit(1)
+		},
+	}
+	return cmd
+}
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "manage the kontext config file",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(newValidateCommand())
+	return cmd
+}
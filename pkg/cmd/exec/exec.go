@@ -0,0 +1,96 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/context"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/signals"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec <context|group/context> -- <command> [args...]",
+		Short: "run a single command against a context, without switching to it",
+		Long: `Runs the command with KUBECONFIG pointing to a temporary kubeconfig, that only contains the given context,
+its cluster and its user. The context is either taken from the current kubeconfig or from the sources of a group,
+written as group/context. The active context, the state and the history are left untouched.
+The exit code of the command is propagated, e.g.: kontext exec prod/kind-prod -- kubectl get pods
+		`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() != 1 || len(args) < 2 {
+				return fmt.Errorf("expected a context, followed by -- and the command")
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			// the command owns stdout, log messages must not interfere with its output
+			log := logger.New().WithWriter(os.Stderr)
+
+			client, err := context.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			apiConfig, err := client.Minify(args[0])
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			// termination signals are passed to the command, the temporary kubeconfig is removed once it has exited
+			forwarder := signals.Forward()
+
+			file, err := os.CreateTemp("", "kontext-exec-*.yaml")
+			if err != nil {
+				log.Error("could not create temporary kubeconfig", log.Args("err", err))
+				os.Exit(1)
+			}
+			err = kubeconfig.Write(file, apiConfig)
+			_ = file.Close()
+			if err != nil {
+				_ = os.Remove(file.Name())
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			command := exec.Command(args[1], args[2:]...)
+			command.Stdin = os.Stdin
+			command.Stdout = os.Stdout
+			command.Stderr = os.Stderr
+			command.Env = append(os.Environ(), "KUBECONFIG="+file.Name())
+
+			log.Debug("running command", log.Args("context", apiConfig.CurrentContext, "command", args[1], "kubeconfig", file.Name()))
+			err = command.Start()
+			if err == nil {
+				forwarder.Add(command.Process)
+				err = command.Wait()
+				forwarder.Remove(command.Process)
+			}
+
+			code := 0
+			var exitError *exec.ExitError
+			if errors.As(err, &exitError) {
+				code = signals.ExitCode(exitError.ProcessState)
+			} else if err != nil {
+				log.Error(err.Error())
+				code = 1
+			}
+
+			err = os.Remove(file.Name())
+			if err != nil {
+				log.Error("could not remove temporary kubeconfig", log.Args("file", file.Name(), "err", err))
+				code = 1
+			}
+			os.Exit(code)
+		},
+	}
+	return cmd
+}
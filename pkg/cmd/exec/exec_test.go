@@ -0,0 +1,122 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/state"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// commandEnvironmentVariable makes the test binary run the exec command with the given context and exit
+const commandEnvironmentVariable = "KONTEXT_TEST_EXEC_CONTEXT"
+
+// TestMain lets the test binary act as a kontext process, that runs a long-running command against a context
+func TestMain(m *testing.M) {
+	contextName, ok := os.LookupEnv(commandEnvironmentVariable)
+	if !ok {
+		os.Exit(m.Run())
+	}
+
+	config.File = os.Getenv(config.ConfigEnvironmentVariable)
+	cmd := NewCommand()
+	cmd.SetArgs([]string{contextName, "--", "sleep", "20"})
+	_ = cmd.Execute()
+	os.Exit(1)
+}
+
+func Test_Exec_Terminate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can not be sent to processes on windows")
+	}
+
+	directory := t.TempDir()
+	temporaryDirectory := t.TempDir()
+	configFile := filepath.Join(directory, "kontext.yaml")
+	kubeconfigFile := filepath.Join(directory, "kubeconfig.yaml")
+
+	err := kubeconfig.WriteFile(kubeconfigFile, &api.Config{
+		Clusters:       map[string]*api.Cluster{"kind-dev": {Server: "https://127.0.0.1:6443"}},
+		AuthInfos:      map[string]*api.AuthInfo{"kind-dev": {Token: "token"}},
+		Contexts:       map[string]*api.Context{"kind-dev": {Cluster: "kind-dev", AuthInfo: "kind-dev"}},
+		CurrentContext: "kind-dev",
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	data := fmt.Sprintf("global:\n  kubeconfig: %s\nstate:\n  file: %s\n", kubeconfigFile, filepath.Join(directory, "state.json"))
+	err = os.WriteFile(configFile, []byte(data), 0600)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	configClient := &config.Client{
+		File: configFile,
+	}
+	currentConfig, err := configClient.Read()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = state.Write(currentConfig, &state.State{})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^Test_Exec_Terminate$")
+	cmd.Env = append(os.Environ(),
+		commandEnvironmentVariable+"=kind-dev",
+		config.ConfigEnvironmentVariable+"="+configFile,
+		config.SessionEnvironmentVariable+"=",
+		"TMPDIR="+temporaryDirectory,
+	)
+	err = cmd.Start()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// wait for the temporary kubeconfig, kontext catches the signals before it is created
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		entries, _ := os.ReadDir(temporaryDirectory)
+		if len(entries) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = cmd.Process.Kill()
+			t.Fatalf("temporary kubeconfig has not been created")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = cmd.Process.Signal(syscall.SIGTERM)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	timer := time.AfterFunc(10*time.Second, func() {
+		_ = cmd.Process.Kill()
+	})
+	err = cmd.Wait()
+	timer.Stop()
+
+	// the command has been terminated by the forwarded signal, kontext exits just like a shell would
+	var exitError *exec.ExitError
+	if !errors.As(err, &exitError) || exitError.ExitCode() != 128+int(syscall.SIGTERM) {
+		t.Errorf("want: exit code '%d', got: '%v'", 128+int(syscall.SIGTERM), err)
+	}
+
+	entries, err := os.ReadDir(temporaryDirectory)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, entry := range entries {
+		t.Errorf("unexpected file: '%s'", entry.Name())
+	}
+}
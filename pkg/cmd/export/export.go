@@ -0,0 +1,148 @@
+package export
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/context"
+	"github.com/orbatschow/kontext/pkg/group"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/file"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	FlattenFlag = "flatten"
+	FileFlag    = "file"
+	// FileMode protects the exported credentials from other users
+	FileMode = 0600
+)
+
+func addFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlattenFlag, false, "inline all certificates and keys, that are referred by a file path")
+	cmd.Flags().StringP(FileFlag, "f", "", "write the kubeconfig to the given file instead of stdout")
+}
+
+// write flattens the api config if requested and writes it to stdout or to the file of the command
+func write(cmd *cobra.Command, apiConfig *api.Config) error {
+	log := logger.New().WithWriter(os.Stderr)
+
+	flatten, err := cmd.Flags().GetBool(FlattenFlag)
+	if err != nil {
+		return err
+	}
+	path, err := cmd.Flags().GetString(FileFlag)
+	if err != nil {
+		return err
+	}
+
+	if flatten {
+		err = kubeconfig.Flatten(apiConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	buffer, err := clientcmd.Write(*apiConfig)
+	if err != nil {
+		return fmt.Errorf("could not serialize kubeconfig, err: '%w'", err)
+	}
+	if len(path) == 0 {
+		_, err = os.Stdout.Write(buffer)
+		return err
+	}
+
+	// exported credentials must not be readable by others, not even while an existing file is replaced
+	err = file.WriteAtomicMode(path, buffer, FileMode)
+	if err != nil {
+		return err
+	}
+	log.Info("exported kubeconfig", log.Args("file", path, "contexts", len(apiConfig.Contexts)))
+	return nil
+}
+
+func newExportContextCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context <context|group/context>",
+		Short: "export a kubeconfig, that only contains the given context, its cluster and its user",
+		Long: `The context is either taken from the current kubeconfig or from the sources of a group, written as group/context.
+		`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New().WithWriter(os.Stderr)
+
+			client, err := context.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			apiConfig, err := client.Minify(args[0])
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			err = write(cmd, apiConfig)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	addFlags(cmd)
+	return cmd
+}
+
+func newExportGroupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "group <name>",
+		Short: "export a kubeconfig, that contains all contexts of the given group",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New().WithWriter(os.Stderr)
+
+			client, err := group.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			apiConfig, err := client.Export(args[0])
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			err = write(cmd, apiConfig)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	addFlags(cmd)
+	return cmd
+}
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export [context|group] [name] as a self-contained kubeconfig",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(newExportContextCommand())
+	cmd.AddCommand(newExportGroupCommand())
+
+	return cmd
+}
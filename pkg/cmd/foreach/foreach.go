@@ -0,0 +1,83 @@
+package foreach
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/foreach"
+	"github.com/orbatschow/kontext/pkg/group"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/signals"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+	var groupName, pattern, selector string
+	var parallel int
+
+	cmd := &cobra.Command{
+		Use:   "foreach [--group name] [--match pattern] [--selector labels] [--parallel n] -- <command> [args...]",
+		Short: "run a command against every context of a group",
+		Long: `Runs the command once per context of the active group, or of the given group, each of them with its own
+temporary kubeconfig. The output of each command is prefixed with the context name, a summary of all exit codes
+and durations is printed afterwards. The exit code is non-zero, if at least one command failed.
+The active context, the state and the history are left untouched.
+		`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.ArgsLenAtDash() != 0 || len(args) == 0 {
+				return fmt.Errorf("expected -- followed by the command")
+			}
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			// the commands own stdout, log messages must not interfere with their output
+			log := logger.New().WithWriter(os.Stderr)
+
+			client, err := group.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			apiConfig, contexts, err := client.Contexts(groupName, pattern, selector)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			if len(contexts) == 0 {
+				log.Warn("no context matches", log.Args("group", groupName, "match", pattern, "selector", selector))
+				return
+			}
+
+			runner := foreach.Runner{
+				APIConfig: apiConfig,
+				Command:   args,
+				Parallel:  parallel,
+				Stdout:    os.Stdout,
+				Stderr:    os.Stderr,
+				// termination signals are passed to the commands, their temporary kubeconfigs are removed afterwards
+				Signals: signals.Forward(),
+			}
+
+			log.Debug("running command", log.Args("contexts", len(contexts), "parallel", parallel))
+			results := runner.Run(contexts)
+
+			err = foreach.BuildTablePrinter(results).Render()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			if foreach.Failed(results) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&groupName, "group", "g", "", "group, that provides the contexts, defaults to the active group")
+	cmd.Flags().StringVarP(&pattern, "match", "m", "", "glob pattern, that selects the contexts by name, defaults to all contexts")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "label selector, that selects the contexts by their labels, defaults to all contexts")
+	cmd.Flags().IntVarP(&parallel, "parallel", "p", foreach.DefaultParallel, "maximum number of commands, that run at the same time")
+
+	return cmd
+}
@@ -0,0 +1,117 @@
+package gc
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/context"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/session"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Expire switches back to the safe context, if the active protected context exceeded its ttl
+// It runs before every command, so all messages are written to stderr and errors are only logged.
+func Expire(_ *cobra.Command, _ []string) {
+	log := logger.New().WithWriter(os.Stderr)
+
+	err := expire(log)
+	// the user stays on the protected context, that must not go unnoticed
+	if errors.Is(err, context.ErrNoSafeContext) {
+		log.Warn("could not expire context", log.Args("error", err.Error()))
+		return
+	}
+	if err != nil {
+		log.Debug("could not expire context", log.Args("error", err.Error()))
+	}
+}
+
+// expire reverts the active context while holding the state lock
+func expire(log *pterm.Logger) error {
+	configClient := &config.Client{
+		File: config.File,
+	}
+	currentConfig, err := configClient.Read()
+	if err != nil {
+		return err
+	}
+
+	// without a state, there is no active context
+	if _, err := os.Stat(currentConfig.State.File); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	lock, err := state.Lock(currentConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lock.Release()
+	}()
+
+	client, err := context.New(config.File)
+	if err != nil {
+		return err
+	}
+
+	previous := client.State.Context.Active
+	safe, err := client.Expire()
+	if err != nil {
+		return err
+	}
+	if len(safe) == 0 {
+		return nil
+	}
+
+	err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
+	if err != nil {
+		return err
+	}
+	err = state.Write(client.Config, client.State)
+	if err != nil {
+		return err
+	}
+
+	log.Warn("switched to safe context, the protected context exceeded its ttl", log.Args("context", previous, "safe", safe))
+	return nil
+}
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "revert expired protected contexts and remove stale sessions",
+		Long: `Switches back to the safe context of the active group, if the active protected context
+has been active for longer than its ttl. Stale sessions are removed as well.
+The ttl is checked before every kontext command, run this command from a shell hook to check it in between.
+		`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			err := expire(log)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			client, err := session.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			removed, err := client.Cleanup()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			log.Info("removed stale sessions", log.Args("count", strconv.Itoa(len(removed))))
+		},
+	}
+	return cmd
+}
@@ -3,19 +3,25 @@ package get
 import (
 	"log"
 	"os"
+	"sort"
 
 	"github.com/orbatschow/kontext/pkg/config"
 	"github.com/orbatschow/kontext/pkg/context"
 	"github.com/orbatschow/kontext/pkg/group"
 	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/namespace"
+	"github.com/orbatschow/kontext/pkg/output"
 	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/pterm/pterm"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 func Init(_ *cobra.Command, _ []string) {
 	// load config
 	configClient := &config.Client{
-		File: config.DefaultConfigPath,
+		File: config.File,
 	}
 	currentConfig, err := configClient.Read()
 	if err != nil {
@@ -29,7 +35,22 @@ func Init(_ *cobra.Command, _ []string) {
 	}
 }
 
+// parseFormat validates the output format and moves all log messages to stderr,
+// if the output is meant to be processed by other tools
+func parseFormat(value string) (output.Format, error) {
+	format, err := output.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	if format != output.FormatTable && format != output.FormatWide {
+		pterm.DefaultLogger.Writer = os.Stderr
+	}
+	return format, nil
+}
+
 func newGetGroupCommand() *cobra.Command {
+	var format string
+
 	cmd := &cobra.Command{
 		Use:    "group [name]",
 		Short:  "get groups, optionally filtered by name",
@@ -42,7 +63,13 @@ func newGetGroupCommand() *cobra.Command {
 				groupName = args[0]
 			}
 
-			groupClient, err := group.New()
+			outputFormat, err := parseFormat(format)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			groupClient, err := group.New(config.File)
 			if err != nil {
 				log.Error(err.Error())
 				os.Exit(1)
@@ -60,18 +87,41 @@ func newGetGroupCommand() *cobra.Command {
 				groups = groupClient.Config.Group.Items
 			}
 
-			printer := groupClient.BuildTablePrinter(groups...)
-			err = printer.Render()
+			err = renderGroups(groupClient, outputFormat, groups...)
 			if err != nil {
 				log.Error(err.Error())
 				os.Exit(1)
 			}
 		},
 	}
+
+	output.AddFlag(cmd, &format)
 	return cmd
 }
 
+func renderGroups(client *group.Client, format output.Format, groups ...config.GroupItem) error {
+	switch format {
+	case output.FormatTable:
+		return client.BuildTablePrinter(groups...).Render()
+	case output.FormatName:
+		return output.WriteNames(os.Stdout, lo.Map(groups, func(item config.GroupItem, _ int) string {
+			return item.Name
+		})...)
+	}
+
+	items, err := client.BuildItems(groups...)
+	if err != nil {
+		return err
+	}
+	if format == output.FormatWide {
+		return client.BuildWideTablePrinter(items...).Render()
+	}
+	return output.Write(os.Stdout, format, items)
+}
+
 func newGetContextCommand() *cobra.Command {
+	var format string
+
 	cmd := &cobra.Command{
 		Use:    "context [name]",
 		Short:  "get contexts, optionally filtered by name",
@@ -83,44 +133,122 @@ func newGetContextCommand() *cobra.Command {
 				contextName = args[0]
 			}
 
-			contextClient, err := context.New()
+			outputFormat, err := parseFormat(format)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			contextClient, err := context.New(config.File)
 			if err != nil {
 				log.Error(err.Error())
 				os.Exit(1)
 			}
 
-			// if a context name is given, find it and render the result
+			// if a context name is given, find it, otherwise render all contexts
+			match := contextClient.List()
 			if len(contextName) != 0 {
-				match, err := contextClient.Get(contextName)
-				if err != nil {
-					log.Error(err.Error())
-					os.Exit(1)
-				}
-				printer := contextClient.BuildTablePrinter(match)
-				err = printer.Render()
-				if err != nil {
-					log.Error(err.Error())
-					os.Exit(1)
-				}
-			} else {
-				// if no context name is given, find all groups and render the result
-				match := contextClient.List()
-				printer := contextClient.BuildTablePrinter(match)
-				err = printer.Render()
+				match, err = contextClient.Get(contextName)
 				if err != nil {
 					log.Error(err.Error())
 					os.Exit(1)
 				}
 			}
+
+			err = renderContexts(contextClient, outputFormat, match)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	output.AddFlag(cmd, &format)
+	return cmd
+}
+
+func renderContexts(client *context.Client, format output.Format, contexts map[string]*api.Context) error {
+	switch format {
+	case output.FormatTable:
+		protected, err := client.Protected(contexts)
+		if err != nil {
+			return err
+		}
+		return client.BuildTablePrinter(contexts, protected).Render()
+	case output.FormatName:
+		names := lo.Keys(contexts)
+		sort.Strings(names)
+		return output.WriteNames(os.Stdout, names...)
+	}
+
+	items, err := client.BuildItems(contexts)
+	if err != nil {
+		return err
+	}
+	if format == output.FormatWide {
+		return client.BuildWideTablePrinter(items...).Render()
+	}
+	return output.Write(os.Stdout, format, items)
+}
+
+func newGetNamespaceCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:    "namespace",
+		Short:  "get the namespace of the active context and all configured namespaces",
+		Args:   cobra.NoArgs,
+		PreRun: Init,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			outputFormat, err := parseFormat(format)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			namespaceClient, err := namespace.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			active, err := namespaceClient.Get()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			namespaces, err := namespaceClient.List()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			switch outputFormat {
+			case output.FormatTable, output.FormatWide:
+				err = namespaceClient.BuildTablePrinter(active, namespaces...).Render()
+			case output.FormatName:
+				err = output.WriteNames(os.Stdout, namespaces...)
+			default:
+				err = output.Write(os.Stdout, outputFormat, namespaceClient.BuildItems(active, namespaces...))
+			}
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
 		},
 	}
+
+	output.AddFlag(cmd, &format)
 	return cmd
 }
 
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "get",
-		Short: "get [context|group] [name], defaults to context",
+		Short: "get [context|group|namespace] [name], defaults to context",
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
 			os.Exit(1)
@@ -129,5 +257,6 @@ func NewCommand() *cobra.Command {
 
 	cmd.AddCommand(newGetGroupCommand())
 	cmd.AddCommand(newGetContextCommand())
+	cmd.AddCommand(newGetNamespaceCommand())
 	return cmd
 }
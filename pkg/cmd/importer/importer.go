@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/orbatschow/kontext/pkg/cmd/set"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/group"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+	var sourceName, directory, name string
+	var contexts map[string]string
+
+	cmd := &cobra.Command{
+		Use:   "import <file> --source <name>",
+		Short: "import a kubeconfig file into the directory of a source",
+		Long: `Validates the kubeconfig file and copies it into a directory, that is included by the given source.
+Without --directory the static part of the first include glob of the source is used.
+The import is aborted, if the file would not be included by the source or if it defines names, that are already
+defined by other files of the source. The active group is reloaded afterwards, if it refers to the source.
+		`,
+		Args:    cobra.ExactArgs(1),
+		PreRun:  set.Init,
+		PostRun: set.Release,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			configClient := &config.Client{
+				File: config.File,
+			}
+			currentConfig, err := configClient.Read()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			sourceItem, ok := lo.Find(currentConfig.Source.Items, func(item config.SourceItem) bool {
+				return item.Name == sourceName
+			})
+			if !ok {
+				log.Error("could not find source", log.Args("source", sourceName))
+				os.Exit(1)
+			}
+
+			if len(name) == 0 {
+				name = filepath.Base(args[0])
+			}
+			target, err := source.ImportTarget(&sourceItem, directory, name)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			conflicts, err := source.Import(currentConfig, &sourceItem, args[0], target, contexts)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			if len(conflicts) > 0 {
+				for _, conflict := range conflicts {
+					log.Warn(conflict.String(), log.Args("source", sourceName, "hint", "rename the contexts with --rename or configure source.items[].rename"))
+				}
+				log.Error("could not import kubeconfig, it conflicts with other files of the source", log.Args("file", args[0]))
+				os.Exit(1)
+			}
+			log.Info("imported kubeconfig", log.Args("file", target, "source", sourceName))
+
+			err = reload(cmd, sourceName)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&sourceName, "source", "s", "", "source, that shall include the kubeconfig file")
+	cmd.Flags().StringVarP(&directory, "directory", "d", "", "target directory, defaults to the static part of the first include glob of the source")
+	cmd.Flags().StringVar(&name, "name", "", "target file name, defaults to the name of the given file")
+	cmd.Flags().StringToStringVarP(&contexts, "rename", "r", nil, "rename contexts of the kubeconfig file, e.g. kind-kind=kind-dev")
+	_ = cmd.MarkFlagRequired("source")
+	set.AddYesFlag(cmd)
+
+	return cmd
+}
+
+// reload reloads the active group, if it refers to the given source
+func reload(cmd *cobra.Command, sourceName string) error {
+	log := logger.New()
+
+	client, err := group.New(config.File)
+	if err != nil {
+		return err
+	}
+	client.Yes, _ = cmd.Flags().GetBool(set.YesFlag)
+
+	active, err := client.Get(client.State.Group.Active)
+	if err != nil {
+		log.Debug("skipping reload, the active group does not exist", log.Args("group", client.State.Group.Active))
+		return nil
+	}
+	sources, err := source.GroupSources(client.Config, active)
+	if err != nil {
+		return err
+	}
+	if !lo.Contains(sources, sourceName) {
+		log.Debug("skipping reload, the active group does not refer to the source", log.Args("source", sourceName))
+		return nil
+	}
+
+	err = client.Reload()
+	if err != nil {
+		return err
+	}
+
+	err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
+	if err != nil {
+		return err
+	}
+
+	return state.Write(client.Config, client.State)
+}
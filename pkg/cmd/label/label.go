@@ -0,0 +1,145 @@
+package label
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/orbatschow/kontext/pkg/cmd/set"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/group"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/label"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label",
+		Short: "attach labels to contexts, groups with a selector contain all matching contexts",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newLabelContextCommand())
+	return cmd
+}
+
+func newLabelContextCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context <name> <key=value|key->...",
+		Short: "set or remove the labels of a context",
+		Long: `Sets each key=value label and removes each key- label of the given context. The labels are kept in the label
+file and take precedence over the labels of the sources and the label items. The active group is reloaded afterwards,
+if it has a selector.
+		`,
+		Example: "kontext label context kind-prod env=prod team=a owner-",
+		Args:    cobra.MinimumNArgs(2),
+		PreRun:  set.Init,
+		PostRun: set.Release,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+			contextName := args[0]
+
+			change, err := label.ParseChange(args[1:])
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			configClient := &config.Client{
+				File: config.File,
+			}
+			currentConfig, err := configClient.Read()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			err = find(currentConfig, contextName)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			store, err := label.Read(currentConfig)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			store.Apply(contextName, change)
+			err = label.Write(currentConfig, store)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			log.Info("labeled context", log.Args("context", contextName, "labels", labels.Set(store[contextName]).String()))
+
+			err = reload(cmd)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	set.AddYesFlag(cmd)
+	return cmd
+}
+
+// find fails, if no source defines the given context
+func find(currentConfig *config.Config, contextName string) error {
+	for _, item := range currentConfig.Source.Items {
+		item := item
+		kubeconfigs, err := source.LoadSource(currentConfig, &item, source.ExecCached)
+		if err != nil {
+			return err
+		}
+		for _, kubeconfig := range kubeconfigs {
+			if _, ok := kubeconfig.APIConfig.Contexts[contextName]; ok {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("could not find context '%s' within any source", contextName)
+}
+
+// reload reloads the active group, if it or any group it refers to has a selector, so it contains all matching contexts
+func reload(cmd *cobra.Command) error {
+	log := logger.New()
+
+	client, err := group.New(config.File)
+	if err != nil {
+		return err
+	}
+	client.Yes, _ = cmd.Flags().GetBool(set.YesFlag)
+
+	active, err := client.Get(client.State.Group.Active)
+	if err != nil {
+		log.Debug("skipping reload, the active group does not exist", log.Args("group", client.State.Group.Active))
+		return nil
+	}
+	selects, err := source.HasSelector(client.Config, active)
+	if err != nil {
+		return err
+	}
+	if !selects {
+		log.Debug("skipping reload, the active group has no selector", log.Args("group", client.State.Group.Active))
+		return nil
+	}
+
+	err = client.Reload()
+	if err != nil {
+		return err
+	}
+
+	err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
+	if err != nil {
+		return err
+	}
+
+	return state.Write(client.Config, client.State)
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/orbatschow/kontext/pkg/cmd/set"
+	"github.com/orbatschow/kontext/pkg/config"
 	"github.com/orbatschow/kontext/pkg/group"
 	"github.com/orbatschow/kontext/pkg/kubeconfig"
 	"github.com/orbatschow/kontext/pkg/logger"
@@ -13,17 +14,19 @@ import (
 
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:    "reload",
-		Short:  "reload the active group",
-		PreRun: set.Init,
+		Use:     "reload",
+		Short:   "reload the active group",
+		PreRun:  set.Init,
+		PostRun: set.Release,
 		Run: func(cmd *cobra.Command, args []string) {
 			log := logger.New()
 
-			client, err := group.New()
+			client, err := group.New(config.File)
 			if err != nil {
 				log.Error(err.Error())
 				os.Exit(1)
 			}
+			client.Yes, _ = cmd.Flags().GetBool(set.YesFlag)
 
 			err = client.Reload()
 			if err != nil {
@@ -31,12 +34,7 @@ func NewCommand() *cobra.Command {
 				os.Exit(1)
 			}
 
-			file, err := os.OpenFile(client.Config.Global.Kubeconfig, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
-			if err != nil {
-				log.Error(err.Error())
-				os.Exit(1)
-			}
-			err = kubeconfig.Write(file, client.APIConfig)
+			err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
 			if err != nil {
 				log.Error(err.Error())
 				os.Exit(1)
@@ -49,5 +47,6 @@ func NewCommand() *cobra.Command {
 			}
 		},
 	}
+	set.AddYesFlag(cmd)
 	return cmd
 }
@@ -0,0 +1,133 @@
+package session
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/session"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+func newInitCommand() *cobra.Command {
+	var pid int
+	var shell string
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "create a new session and print the shell code, that activates it",
+		Long: `Creates a copy of the current kubeconfig and state, that is used exclusively by a single shell.
+Activate the session by evaluating the output, e.g. 'eval "$(kontext session init)"'.
+The session belongs to the parent process by default and is removed by the next cleanup, after the process exited.
+		`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// the output is evaluated by the shell, so all log messages have to be written to stderr
+			pterm.DefaultLogger.Writer = os.Stderr
+			log := logger.New()
+
+			client, err := session.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			_, err = client.Cleanup()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			instance, err := client.Create(pid)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			if len(shell) == 0 {
+				shell = session.DetectShell(session.Shell())
+			}
+			script, err := client.Script(instance, shell)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			pterm.Print(script)
+		},
+	}
+
+	cmd.Flags().IntVar(&pid, "pid", os.Getppid(), "process, that owns the session")
+	cmd.Flags().StringVar(&shell, "shell", "", "shell dialect of the output, one of: posix, fish, powershell")
+	return cmd
+}
+
+func newListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list all sessions",
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			client, err := session.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			sessions, err := client.List()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			printer := client.BuildTablePrinter(os.Getenv(config.SessionEnvironmentVariable), sessions...)
+			err = printer.Render()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	return cmd
+}
+
+func newCleanupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "remove all sessions, whose shell does not exist anymore",
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			client, err := session.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			removed, err := client.Cleanup()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			log.Info("removed stale sessions", log.Args("count", strconv.Itoa(len(removed))))
+		},
+	}
+	return cmd
+}
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "manage sessions, that isolate the kubeconfig of a single shell",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(newCleanupCommand())
+	cmd.AddCommand(newInitCommand())
+	cmd.AddCommand(newListCommand())
+	return cmd
+}
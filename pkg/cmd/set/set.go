@@ -10,14 +10,34 @@ import (
 	"github.com/orbatschow/kontext/pkg/group"
 	"github.com/orbatschow/kontext/pkg/kubeconfig"
 	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/namespace"
 	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/orbatschow/kontext/pkg/utils/file"
 	"github.com/spf13/cobra"
 )
 
-func Init(_ *cobra.Command, _ []string) {
+// lock guards the kubeconfig and state file, until the command has written all changes
+var lock *file.Lock
+
+// YesFlag skips the confirmation of protected contexts
+const YesFlag = "yes"
+
+// AddYesFlag registers the flag, that skips the confirmation of protected contexts
+func AddYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP(YesFlag, "y", false, "switch to protected contexts without confirmation")
+}
+
+// Lock acquires the lock for the kubeconfig and state file, it is held until Release
+// is called or the process exits
+func Lock(_ *cobra.Command, _ []string) {
+	_ = acquire()
+}
+
+// acquire reads the config, initializes the state and acquires the lock, the config is returned to the caller
+func acquire() *config.Config {
 	// load currentConfig
 	configClient := &config.Client{
-		File: config.DefaultConfigPath,
+		File: config.File,
 	}
 	currentConfig, err := configClient.Read()
 	if err != nil {
@@ -30,6 +50,24 @@ func Init(_ *cobra.Command, _ []string) {
 		log.Fatal(err.Error())
 	}
 
+	lock, err = state.Lock(currentConfig)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return currentConfig
+}
+
+// Release releases the lock, that has been acquired by Lock or Init
+func Release(_ *cobra.Command, _ []string) {
+	err := lock.Release()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func Init(_ *cobra.Command, _ []string) {
+	currentConfig := acquire()
+
 	// read the current currentState
 	currentState, err := state.Read(currentConfig)
 	if err != nil {
@@ -47,7 +85,7 @@ func Init(_ *cobra.Command, _ []string) {
 	}
 }
 
-func newSetGroupCommand(_ *cobra.Command, args []string) {
+func newSetGroupCommand(cmd *cobra.Command, args []string) {
 	log := logger.New()
 	var groupName string
 	if len(args) == 0 {
@@ -56,11 +94,12 @@ func newSetGroupCommand(_ *cobra.Command, args []string) {
 		groupName = args[0]
 	}
 
-	client, err := group.New()
+	client, err := group.New(config.File)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
+	client.Yes, _ = cmd.Flags().GetBool(YesFlag)
 
 	err = client.Set(groupName)
 	if err != nil {
@@ -68,12 +107,7 @@ func newSetGroupCommand(_ *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	file, err := os.OpenFile(client.Config.Global.Kubeconfig, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		log.Error(err.Error())
-		os.Exit(1)
-	}
-	err = kubeconfig.Write(file, client.APIConfig)
+	err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
@@ -86,7 +120,7 @@ func newSetGroupCommand(_ *cobra.Command, args []string) {
 	}
 }
 
-func NewSetContextCommand(_ *cobra.Command, args []string) {
+func NewSetContextCommand(cmd *cobra.Command, args []string) {
 	log := logger.New()
 	var contextName string
 	if len(args) == 0 {
@@ -95,11 +129,12 @@ func NewSetContextCommand(_ *cobra.Command, args []string) {
 		contextName = args[0]
 	}
 
-	client, err := context.New()
+	client, err := context.New(config.File)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
+	client.Yes, _ = cmd.Flags().GetBool(YesFlag)
 
 	err = client.Set(contextName)
 	if err != nil {
@@ -107,12 +142,39 @@ func NewSetContextCommand(_ *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	file, err := os.OpenFile(client.Config.Global.Kubeconfig, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	err = kubeconfig.Write(file, client.APIConfig)
+
+	err = state.Write(client.Config, client.State)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func newSetNamespaceCommand(_ *cobra.Command, args []string) {
+	log := logger.New()
+	var namespaceName string
+	if len(args) > 0 {
+		namespaceName = args[0]
+	}
+
+	client, err := namespace.New(config.File)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	err = client.Set(namespaceName)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
@@ -128,7 +190,7 @@ func NewSetContextCommand(_ *cobra.Command, args []string) {
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "set [name]",
-		Short: "set [context|group] [name]",
+		Short: "set [context|group|namespace] [name]",
 		Long: `Invoking this command without a parameter will spawn an interactive selection dialog.
 When providing a context name, the switch will be performed immediately.
 '-' is a reserved context name, that will cause a switch to the previously active context.
@@ -147,8 +209,9 @@ If neither, context nor group is specified, this command will set the context.
 When providing a group name, the switch will be performed immediately.
 '-' is a reserved group name, that will cause a switch to the previously active group.
 		`,
-		PreRun: Init,
-		Run:    newSetGroupCommand,
+		PreRun:  Init,
+		Run:     newSetGroupCommand,
+		PostRun: Release,
 	}
 
 	setContextCommand := &cobra.Command{
@@ -157,13 +220,33 @@ When providing a group name, the switch will be performed immediately.
 		Long: `Invoking this command without a parameter will spawn an interactive selection dialog.
 When providing a context name, the switch will be performed immediately.
 '-' is a reserved context name, that will cause a switch to the previously active context.
+Protected contexts require the context name to be typed, unless --yes is given.
 		`,
-		PreRun: Init,
-		Run:    NewSetContextCommand,
+		PreRun:  Init,
+		Run:     NewSetContextCommand,
+		PostRun: Release,
+	}
+
+	setNamespaceCommand := &cobra.Command{
+		Use:   "namespace [name]",
+		Short: "set the namespace of the active context",
+		Long: `Invoking this command without a parameter will spawn an interactive selection dialog,
+that offers all namespaces configured for the active context.
+When providing a namespace name, the switch will be performed immediately.
+'-' is a reserved namespace name, that will cause a switch to the previously active namespace of the active context.
+		`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  Init,
+		Run:     newSetNamespaceCommand,
+		PostRun: Release,
 	}
 
+	AddYesFlag(setGroupCommand)
+	AddYesFlag(setContextCommand)
+
 	cmd.AddCommand(setGroupCommand)
 	cmd.AddCommand(setContextCommand)
+	cmd.AddCommand(setNamespaceCommand)
 
 	return cmd
 }
@@ -0,0 +1,122 @@
+package set
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// contextEnvironmentVariable makes the test binary switch to the given context and exit
+const contextEnvironmentVariable = "KONTEXT_TEST_SET_CONTEXT"
+
+// TestMain lets the test binary act as a kontext process, that switches the context through the command hooks
+func TestMain(m *testing.M) {
+	contextName, ok := os.LookupEnv(contextEnvironmentVariable)
+	if !ok {
+		os.Exit(m.Run())
+	}
+
+	cmd := &cobra.Command{}
+	AddYesFlag(cmd)
+	config.File = os.Getenv(config.ConfigEnvironmentVariable)
+
+	Init(cmd, nil)
+	NewSetContextCommand(cmd, []string{contextName})
+	Release(cmd, nil)
+	os.Exit(0)
+}
+
+func Test_Set_Concurrent(t *testing.T) {
+	directory := t.TempDir()
+	configFile := filepath.Join(directory, "kontext.yaml")
+	kubeconfigFile := filepath.Join(directory, "kubeconfig.yaml")
+	contextNames := []string{"kind-dev", "kind-local", "kind-prod"}
+
+	apiConfig := &api.Config{
+		Clusters:       map[string]*api.Cluster{},
+		AuthInfos:      map[string]*api.AuthInfo{},
+		Contexts:       map[string]*api.Context{},
+		CurrentContext: contextNames[0],
+	}
+	for _, contextName := range contextNames {
+		apiConfig.Clusters[contextName] = &api.Cluster{Server: "https://" + contextName}
+		apiConfig.AuthInfos[contextName] = &api.AuthInfo{Token: contextName}
+		apiConfig.Contexts[contextName] = &api.Context{Cluster: contextName, AuthInfo: contextName}
+	}
+	err := kubeconfig.WriteFile(kubeconfigFile, apiConfig)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	data := fmt.Sprintf("global:\n  kubeconfig: %s\nstate:\n  file: %s\n", kubeconfigFile, filepath.Join(directory, "state.json"))
+	err = os.WriteFile(configFile, []byte(data), 0600)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// every process performs the full read-modify-write cycle of a context switch
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(contextName string) {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^Test_Set_Concurrent$")
+			cmd.Env = append(os.Environ(),
+				contextEnvironmentVariable+"="+contextName,
+				config.ConfigEnvironmentVariable+"="+configFile,
+				config.SessionEnvironmentVariable+"=",
+			)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				err = fmt.Errorf("could not set context '%s', err: '%w', output: '%s'", contextName, err, output)
+			}
+			errs <- err
+		}(contextNames[i%len(contextNames)])
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error, err: '%v'", err)
+		}
+	}
+
+	// the kubeconfig has to be complete and in sync with the state
+	file, err := os.Open(kubeconfigFile)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer file.Close()
+	got, err := kubeconfig.Read(file)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if len(got.Contexts) != len(contextNames) {
+		t.Errorf("want: '%d' contexts, got: '%d'", len(contextNames), len(got.Contexts))
+	}
+
+	configClient := &config.Client{
+		File: configFile,
+	}
+	currentConfig, err := configClient.Read()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	gotState, err := state.Read(currentConfig)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if gotState.Context.Active != got.CurrentContext {
+		t.Errorf("want: '%s', got: '%s'", got.CurrentContext, gotState.Context.Active)
+	}
+}
@@ -0,0 +1,85 @@
+package shell
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/session"
+	"github.com/orbatschow/kontext/pkg/utils/signals"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "spawn a new shell with its own session",
+		Long: `Spawns the shell from $SHELL with a copy of the current kubeconfig and state.
+Context and group switches within this shell do not affect any other shell.
+The session is removed as soon as the shell exits.
+		`,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			client, err := session.New(config.File)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			_, err = client.Cleanup()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			instance, err := client.Create(os.Getpid())
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			shell := exec.Command(session.Shell())
+			shell.Stdin = os.Stdin
+			shell.Stdout = os.Stdout
+			shell.Stderr = os.Stderr
+			shell.Env = append(os.Environ(),
+				"KUBECONFIG="+client.Kubeconfig(instance.ID),
+				config.SessionEnvironmentVariable+"="+instance.ID,
+				// kontext within the shell has to use the same config, regardless of the working directory
+				config.ConfigEnvironmentVariable+"="+config.File,
+			)
+
+			// termination signals are passed to the shell, the session is removed once it has exited
+			forwarder := signals.Forward()
+
+			log.Info("starting session", log.Args("session", instance.ID))
+			err = shell.Start()
+			if err == nil {
+				forwarder.Add(shell.Process)
+				err = shell.Wait()
+				forwarder.Remove(shell.Process)
+			}
+
+			code := 0
+			var exitError *exec.ExitError
+			if errors.As(err, &exitError) {
+				code = signals.ExitCode(exitError.ProcessState)
+			} else if err != nil {
+				log.Error(err.Error())
+				code = 1
+			}
+
+			err = client.Delete(instance.ID)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			log.Info("finished session", log.Args("session", instance.ID))
+			os.Exit(code)
+		},
+	}
+	return cmd
+}
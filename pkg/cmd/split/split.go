@@ -0,0 +1,84 @@
+package split
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+	var directory, fileTemplate string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "split <file> --out <directory>",
+		Short: "split a kubeconfig file into one file per context",
+		Long: `Writes one kubeconfig file per context, that only contains the context, its cluster and its user.
+The file names are computed by a template, that has access to {{ .Context }}, {{ .Cluster }}, {{ .User }} and
+{{ .Namespace }}. Relative paths are resolved against the given file. Existing files are only replaced with --force.
+The output directory can be used as include glob of a source afterwards.
+		`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+
+			apiConfig, err := kubeconfig.ReadFile(args[0])
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			files, err := kubeconfig.Split(apiConfig, fileTemplate)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			names := lo.Keys(files)
+			sort.Strings(names)
+
+			// check all files first, so nothing is written if a single file exists
+			if !force {
+				for _, name := range names {
+					path := filepath.Join(directory, name)
+					_, err := os.Stat(path)
+					if err == nil {
+						log.Error("file already exists, use --force to replace it", log.Args("file", path))
+						os.Exit(1)
+					}
+					if !errors.Is(err, os.ErrNotExist) {
+						log.Error("could not check file", log.Args("file", path, "err", err))
+						os.Exit(1)
+					}
+				}
+			}
+
+			for _, name := range names {
+				path := filepath.Join(directory, name)
+				err = os.MkdirAll(filepath.Dir(path), 0700)
+				if err != nil {
+					log.Error("could not create directory", log.Args("directory", filepath.Dir(path), "err", err))
+					os.Exit(1)
+				}
+				err = kubeconfig.WriteFile(path, files[name])
+				if err != nil {
+					log.Error(err.Error())
+					os.Exit(1)
+				}
+				log.Info("wrote kubeconfig", log.Args("context", files[name].CurrentContext, "file", path))
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&directory, "out", "o", "", "output directory")
+	cmd.Flags().StringVarP(&fileTemplate, "template", "t", kubeconfig.DefaultSplitTemplate, "file name template")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "replace existing files")
+	_ = cmd.MarkFlagRequired("out")
+
+	return cmd
+}
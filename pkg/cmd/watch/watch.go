@@ -0,0 +1,99 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/cmd/set"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/orbatschow/kontext/pkg/utils/signals"
+	"github.com/orbatschow/kontext/pkg/watch"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// detachedFlag is passed to the background process, that is started by --daemon
+const detachedFlag = "detached"
+
+func NewCommand() *cobra.Command {
+	var debounce time.Duration
+	var daemon, detached bool
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "reload the active group, whenever a file of its sources changes",
+		Long: `Watches the directories behind all include globs of the active group and reloads the group, once the changed
+files settled for the debounce duration. The active context and its namespace are kept, if the group still provides
+the context. The watched directories follow switches of the active group. With --daemon, the watcher runs in the
+background and writes its output to watch.log next to the state file.
+		`,
+		Example: "kontext watch --daemon --debounce 2s",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logger.New()
+			yes, _ := cmd.Flags().GetBool(set.YesFlag)
+
+			configClient := &config.Client{
+				File: config.File,
+			}
+			currentConfig, err := configClient.Read()
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			err = state.Init(currentConfig)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+
+			if daemon {
+				daemonArgs := []string{
+					"watch", "--" + detachedFlag,
+					"--config", config.File,
+					"--debounce", debounce.String(),
+					"--verbosity", strconv.Itoa(logger.Verbosity),
+					"--" + set.YesFlag + "=" + strconv.FormatBool(yes),
+				}
+				pid, err := watch.Daemonize(daemonArgs, watch.LogFile(currentConfig))
+				if err != nil {
+					log.Error(err.Error())
+					os.Exit(1)
+				}
+				log.Info("started watcher in the background", log.Args("pid", strconv.Itoa(pid), "log", watch.LogFile(currentConfig)))
+				return
+			}
+			// the output of a background process is written to a file
+			if detached {
+				pterm.DisableColor()
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), signals.Termination...)
+			defer stop()
+
+			watcher := &watch.Watcher{
+				File:     config.File,
+				Debounce: debounce,
+				Yes:      yes,
+			}
+			log.Info("watching sources of the active group", log.Args("pid", strconv.Itoa(os.Getpid())))
+			err = watcher.Run(ctx)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&debounce, "debounce", watch.DefaultDebounce, "duration without further changes, before the group is reloaded")
+	cmd.Flags().BoolVarP(&daemon, "daemon", "d", false, "run the watcher in the background")
+	cmd.Flags().BoolVar(&detached, detachedFlag, false, "the watcher runs in the background")
+	_ = cmd.Flags().MarkHidden(detachedFlag)
+	set.AddYesFlag(cmd)
+	return cmd
+}
@@ -1,20 +1,51 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/structs"
+	"github.com/orbatschow/kontext/pkg/logger"
 )
 
 var (
 	DefaultConfigPath = filepath.Join(xdg.ConfigHome, "kontext", "kontext.yaml")
 )
 
+const (
+	// SessionEnvironmentVariable holds the id of the active session
+	SessionEnvironmentVariable = "KONTEXT_SESSION"
+	SessionKubeconfigFile      = "kubeconfig.yaml"
+	SessionStateFile           = "state.json"
+	// PreviewEnvironmentVariable is set for the preview commands of fzf, they must not wait for the lock of the
+	// selecting kontext process
+	PreviewEnvironmentVariable = "KONTEXT_PREVIEW"
+)
+
+const (
+	SelectorBuiltin = "builtin"
+	SelectorFzf     = "fzf"
+)
+
+// RegexPrefix marks a context pattern of a source as regular expression, all other patterns are glob patterns
+const RegexPrefix = "regex:"
+
+// CompileRegex compiles the regular expression of a context pattern, it has to match the whole context name
+func CompileRegex(expression string) (*regexp.Regexp, error) {
+	// compile the plain expression first, otherwise e.g. 'a)|(b' would become valid by anchoring it
+	if _, err := regexp.Compile(expression); err != nil {
+		return nil, err
+	}
+	return regexp.Compile("^(?:" + expression + ")$")
+}
+
 const (
 	DefaultStateHistoryLimit   = 10
 	DefaultBackupRevisionLimit = 10
@@ -26,15 +57,23 @@ type Client struct {
 }
 
 type Config struct {
-	Global Global `json:"global,omitempty"`
-	State  State  `json:"state,omitempty"`
-	Backup Backup `json:"backup,omitempty"`
-	Group  Group  `json:"group,omitempty"`
-	Source Source `json:"source,omitempty"`
+	Global  Global   `json:"global,omitempty"`
+	State   State    `json:"state,omitempty"`
+	Backup  Backup   `json:"backup,omitempty"`
+	Group   Group    `json:"group,omitempty"`
+	Source  Source   `json:"source,omitempty"`
+	Context Contexts `json:"context,omitempty"`
+	Session Session  `json:"session,omitempty"`
+	Cache   Cache    `json:"cache,omitempty"`
+	Label   Label    `json:"label,omitempty"`
 }
 
 type Global struct {
 	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// fail on every invalid config instead of logging a warning
+	Strict bool `json:"strict,omitempty"`
+	// select contexts and groups with the builtin selector or with fzf, defaults to builtin
+	Selector string `json:"selector,omitempty"`
 }
 
 // State configuration options
@@ -42,6 +81,7 @@ type State struct {
 	// path of the state file
 	File    string  `json:"file,omitempty"`
 	History History `json:"history,omitempty"`
+	Lock    Lock    `json:"lock,omitempty"`
 }
 
 type History struct {
@@ -49,6 +89,11 @@ type History struct {
 	Size int `json:"size"`
 }
 
+type Lock struct {
+	// set the maximum duration to wait for other kontext processes, that modify the kubeconfig and state
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
 // Backup configuration options
 type Backup struct {
 	// enable/disable the backup, defaults to true
@@ -69,11 +114,25 @@ type GroupItem struct {
 	Name    string   `json:"name"`
 	Context Context  `json:"context,omitempty"`
 	Sources []string `json:"sources"`
+	// other groups, whose contexts are part of this group
+	Groups []string `json:"groups,omitempty"`
+	// sources, that are removed from the sources of this group and the groups it refers to
+	ExcludeSources []string `json:"excludeSources,omitempty"`
+	// glob patterns of contexts, that are removed from this group
+	ExcludeContexts []string `json:"excludeContexts,omitempty"`
+	// require a confirmation, before any context of this group becomes active
+	Protected bool `json:"protected,omitempty"`
+	// label selector, e.g. env in (dev,staging),team=a, the group only contains matching contexts
+	// A group with a selector, but without sources and groups, selects from all sources.
+	Selector string `json:"selector,omitempty"`
 }
 
 type Context struct {
 	Default   string    `json:"default"`
 	Selection Selection `json:"selection"`
+	// switch to the safe context, once a protected context has been active for longer than the ttl
+	TTL  time.Duration `json:"ttl,omitempty"`
+	Safe string        `json:"safe,omitempty"`
 }
 
 type Selection struct {
@@ -90,10 +149,112 @@ type SourceItem struct {
 	Name    string   `json:"name"`
 	Include []string `json:"include,omitempty"`
 	Exclude []string `json:"exclude"`
+	// rename all clusters, users and contexts of this source, before it is merged with other sources
+	Rename Rename `json:"rename,omitempty"`
+	// generate a kubeconfig with a command, in addition to the included files
+	Exec Exec `json:"exec,omitempty"`
+	// require a confirmation, before any context of this source becomes active
+	Protected bool `json:"protected,omitempty"`
+	// labels, that are attached to all contexts of this source
+	Labels map[string]string `json:"labels,omitempty"`
+	// filter the contexts of this source, clusters and users, that are no longer referred by a context, are removed
+	Contexts ContextFilter `json:"contexts,omitempty"`
 }
 
-// Read reads the current config file and serialize it with koanf
+// ContextFilter keeps all contexts, that match any include pattern and no exclude pattern, all contexts are
+// included, if there is no include pattern. Patterns are matched against the renamed contexts.
+type ContextFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Rename rules, the template is applied first, the prefix and suffix afterwards
+type Rename struct {
+	Prefix string `json:"prefix,omitempty"`
+	Suffix string `json:"suffix,omitempty"`
+	// go template, that has access to {{ .Name }}, {{ .Source }} and {{ .File }}, the file name without extension
+	Template string `json:"template,omitempty"`
+}
+
+// Exec runs a command, whose stdout is parsed as kubeconfig
+type Exec struct {
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	Env     []EnvVar `json:"env,omitempty"`
+	// reuse the generated kubeconfig, until it is older than the ttl, defaults to 0, which runs the command each time the group is set
+	TTL time.Duration `json:"ttl,omitempty"`
+	// abort the command, if it does not finish in time, defaults to 0, which waits forever
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// EnvVar is added to the environment of the exec command
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Contexts holds configuration options, that apply to all contexts matching the item name
+type Contexts struct {
+	Items []ContextItem `json:"items"`
+}
+
+type ContextItem struct {
+	// glob pattern, that is matched against the context name
+	Name string `json:"name"`
+	// namespaces, that are offered by the interactive namespace selection
+	Namespaces []string `json:"namespaces,omitempty"`
+	// require a confirmation, before a matching context becomes active
+	Protected bool `json:"protected,omitempty"`
+	// overrides the ttl of the group for matching protected contexts
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// Session configuration options
+type Session struct {
+	// set the directory, that contains the kubeconfig and state file of each session
+	Directory string `json:"directory,omitempty"`
+}
+
+// Cache configuration options
+type Cache struct {
+	// set the directory, that contains the kubeconfig files generated by exec sources
+	Directory string `json:"directory,omitempty"`
+}
+
+// Label configuration options, labels are attached to contexts and matched by the selector of a group
+type Label struct {
+	// set the file, that contains the labels of `kontext label context`
+	File  string      `json:"file,omitempty"`
+	Items []LabelItem `json:"items,omitempty"`
+}
+
+// LabelItem attaches labels to all contexts, that are defined by a matching kubeconfig file
+type LabelItem struct {
+	// glob pattern, that is matched against the kubeconfig file
+	File   string            `json:"file"`
+	Labels map[string]string `json:"labels"`
+}
+
+// Read reads and validates the current config file
+// Issues are logged as warnings, unless strict validation is enabled, which turns them into an error.
 func (r *Client) Read() (*Config, error) {
+	config, err := r.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.report(config)
+	if err != nil {
+		return nil, err
+	}
+
+	applySession(config)
+
+	return config, nil
+}
+
+// Load reads the current config file and serialize it with koanf, without validating it or applying the active session
+func (r *Client) Load() (*Config, error) {
 	instance := koanf.New(".")
 	var config *Config
 
@@ -118,6 +279,15 @@ func (r *Client) Read() (*Config, error) {
 			},
 			File: filepath.Join(xdg.StateHome, "kontext", "state.json"),
 		},
+		Session: Session{
+			Directory: filepath.Join(xdg.RuntimeDir, "kontext", "sessions"),
+		},
+		Cache: Cache{
+			Directory: filepath.Join(xdg.CacheHome, "kontext"),
+		},
+		Label: Label{
+			File: filepath.Join(xdg.StateHome, "kontext", "labels.json"),
+		},
 	}, "koanf"), nil)
 	if err != nil {
 		return nil, err
@@ -137,6 +307,12 @@ func expandEnvironment(config *Config) {
 	config.Global.Kubeconfig = os.ExpandEnv(config.Global.Kubeconfig)
 	config.Backup.Directory = os.ExpandEnv(config.Backup.Directory)
 	config.State.File = os.ExpandEnv(config.State.File)
+	config.Session.Directory = os.ExpandEnv(config.Session.Directory)
+	config.Cache.Directory = os.ExpandEnv(config.Cache.Directory)
+	config.Label.File = os.ExpandEnv(config.Label.File)
+	for i, item := range config.Label.Items {
+		config.Label.Items[i].File = os.ExpandEnv(item.File)
+	}
 
 	for i, source := range config.Source.Items {
 		for j, include := range source.Include {
@@ -145,6 +321,42 @@ func expandEnvironment(config *Config) {
 		for j, exclude := range source.Exclude {
 			source.Exclude[j] = os.ExpandEnv(exclude)
 		}
+		source.Exec.Command = os.ExpandEnv(source.Exec.Command)
+		for j, arg := range source.Exec.Args {
+			source.Exec.Args[j] = os.ExpandEnv(arg)
+		}
 		config.Source.Items[i] = source
 	}
 }
+
+// ValidateSession fails for session ids, that would refer to a directory outside the session directory
+func ValidateSession(id string) error {
+	if id != filepath.Base(id) || id == "." || id == ".." {
+		return fmt.Errorf("invalid session id '%s', it must not contain a path", id)
+	}
+	return nil
+}
+
+// applySession redirects the kubeconfig and state file into the session directory, if a session is active
+func applySession(config *Config) {
+	log := logger.New().WithWriter(os.Stderr)
+
+	id := os.Getenv(SessionEnvironmentVariable)
+	if len(id) == 0 {
+		return
+	}
+	err := ValidateSession(id)
+	if err != nil {
+		log.Warn("ignoring session", log.Args("session", id, "error", err.Error()))
+		return
+	}
+
+	directory := filepath.Join(config.Session.Directory, id)
+	if _, err := os.Stat(directory); err != nil {
+		log.Warn("ignoring session, it does not exist anymore", log.Args("session", id, "directory", directory))
+		return
+	}
+
+	config.Global.Kubeconfig = filepath.Join(directory, SessionKubeconfigFile)
+	config.State.File = filepath.Join(directory, SessionStateFile)
+}
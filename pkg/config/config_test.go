@@ -11,6 +11,12 @@ import (
 )
 
 func Test_Read(t *testing.T) {
+	sessionDirectory := t.TempDir()
+	err := os.Mkdir(filepath.Join(sessionDirectory, "kind"), 0700)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
 	type args struct {
 		Environment map[string]string
 		Reader      *Client
@@ -93,6 +99,24 @@ func Test_Read(t *testing.T) {
 						},
 					},
 				},
+				Context: Contexts{
+					Items: []ContextItem{
+						{
+							Name:       "kind-*",
+							Namespaces: []string{"default", "kube-system"},
+						},
+					},
+				},
+				Session: Session{
+					Directory: filepath.Join(xdg.RuntimeDir, "kontext", "sessions"),
+				},
+				Cache: Cache{
+					Directory: filepath.Join(xdg.CacheHome, "kontext"),
+				},
+				Label: Label{
+					File:  filepath.Join(xdg.StateHome, "kontext", "labels.json"),
+					Items: []LabelItem{},
+				},
 			},
 			wantErr: false,
 		},
@@ -147,6 +171,127 @@ func Test_Read(t *testing.T) {
 				Source: Source{
 					Items: []SourceItem{},
 				},
+				Context: Contexts{
+					Items: []ContextItem{},
+				},
+				Session: Session{
+					Directory: filepath.Join(xdg.RuntimeDir, "kontext", "sessions"),
+				},
+				Cache: Cache{
+					Directory: filepath.Join(xdg.CacheHome, "kontext"),
+				},
+				Label: Label{
+					File:  filepath.Join(xdg.StateHome, "kontext", "labels.json"),
+					Items: []LabelItem{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should redirect the kubeconfig and state file into the active session",
+			before: func(t *testing.T, environment map[string]string) {
+				for key, value := range environment {
+					t.Setenv(key, value)
+				}
+			},
+			args: args{
+				Environment: map[string]string{
+					"KONTEXT_TEST_SESSION_DIRECTORY": sessionDirectory,
+					SessionEnvironmentVariable:       "kind",
+				},
+				Reader: &Client{
+					File: func() string {
+						_, caller, _, _ := runtime.Caller(0)
+						path := filepath.Join(caller, "..", "testdata", "04-valid-config-session.yaml")
+						return path
+					}(),
+				},
+			},
+			want: &Config{
+				Global: Global{
+					Kubeconfig: filepath.Join(sessionDirectory, "kind", SessionKubeconfigFile),
+				},
+				Backup: Backup{
+					Enabled:   true,
+					Revisions: DefaultBackupRevisionLimit,
+					Directory: filepath.Join(xdg.DataHome, "kontext", "backup"),
+				},
+				State: State{
+					File: filepath.Join(sessionDirectory, "kind", SessionStateFile),
+				},
+				Group: Group{
+					Items: []GroupItem{},
+				},
+				Source: Source{
+					Items: []SourceItem{},
+				},
+				Context: Contexts{
+					Items: []ContextItem{},
+				},
+				Session: Session{
+					Directory: sessionDirectory,
+				},
+				Cache: Cache{
+					Directory: filepath.Join(xdg.CacheHome, "kontext"),
+				},
+				Label: Label{
+					File:  filepath.Join(xdg.StateHome, "kontext", "labels.json"),
+					Items: []LabelItem{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should ignore the session, as its id refers to a directory outside the session directory",
+			before: func(t *testing.T, environment map[string]string) {
+				for key, value := range environment {
+					t.Setenv(key, value)
+				}
+			},
+			args: args{
+				Environment: map[string]string{
+					"KONTEXT_TEST_SESSION_DIRECTORY": sessionDirectory,
+					SessionEnvironmentVariable:       filepath.Join("..", filepath.Base(sessionDirectory), "kind"),
+				},
+				Reader: &Client{
+					File: func() string {
+						_, caller, _, _ := runtime.Caller(0)
+						path := filepath.Join(caller, "..", "testdata", "04-valid-config-session.yaml")
+						return path
+					}(),
+				},
+			},
+			want: &Config{
+				Global: Global{
+					Kubeconfig: os.ExpandEnv("$HOME/.config/kontext/kubeconfig.yaml"),
+				},
+				Backup: Backup{
+					Enabled:   true,
+					Revisions: DefaultBackupRevisionLimit,
+					Directory: filepath.Join(xdg.DataHome, "kontext", "backup"),
+				},
+				State: State{
+					File: os.ExpandEnv("$HOME/.local/state/kontext/state.json"),
+				},
+				Group: Group{
+					Items: []GroupItem{},
+				},
+				Source: Source{
+					Items: []SourceItem{},
+				},
+				Context: Contexts{
+					Items: []ContextItem{},
+				},
+				Session: Session{
+					Directory: sessionDirectory,
+				},
+				Cache: Cache{
+					Directory: filepath.Join(xdg.CacheHome, "kontext"),
+				},
+				Label: Label{
+					File:  filepath.Join(xdg.StateHome, "kontext", "labels.json"),
+					Items: []LabelItem{},
+				},
 			},
 			wantErr: false,
 		},
@@ -174,3 +319,43 @@ func Test_Read(t *testing.T) {
 		})
 	}
 }
+
+func Test_ValidateSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{
+			name: "should accept a plain session id",
+			id:   "1681000000000-4711",
+		},
+		{
+			name:    "should throw an error, as the session id contains a path",
+			id:      filepath.Join("..", "..", "kind"),
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the session id refers to the parent directory",
+			id:      "..",
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the session id refers to the session directory itself",
+			id:      ".",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSession(tt.id)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+		})
+	}
+}
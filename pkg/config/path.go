@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ConfigEnvironmentVariable selects the config file, unless the --config flag is given
+	ConfigEnvironmentVariable = "KONTEXT_CONFIG"
+	// ProjectConfigFile is searched in the working directory and all of its parents
+	ProjectConfigFile = ".kontext.yaml"
+)
+
+// File is the config file, that has been selected by the --config flag
+// It is replaced by the resolved config file, before any command is executed.
+var File string
+
+// Resolve returns the config file, that shall be used, the first match wins:
+// the given file, $KONTEXT_CONFIG, .kontext.yaml in the working directory or any parent directory
+// and finally the default config file
+func Resolve(file string) (string, error) {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("could not get working directory, err: '%w'", err)
+	}
+	return resolve(file, workingDirectory)
+}
+
+func resolve(file string, directory string) (string, error) {
+	if len(file) > 0 {
+		return file, nil
+	}
+
+	if file, ok := os.LookupEnv(ConfigEnvironmentVariable); ok && len(file) > 0 {
+		return file, nil
+	}
+
+	file, err := search(directory)
+	if err != nil {
+		return "", err
+	}
+	if len(file) > 0 {
+		return file, nil
+	}
+
+	return DefaultConfigPath, nil
+}
+
+// search walks from the given directory up to the root and returns the first project config file
+func search(directory string) (string, error) {
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve directory, err: '%w'", err)
+	}
+
+	for {
+		file := filepath.Join(directory, ProjectConfigFile)
+		info, err := os.Stat(file)
+		if err == nil && !info.IsDir() {
+			return file, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("could not stat project config file, err: '%w'", err)
+		}
+
+		parent := filepath.Dir(directory)
+		if parent == directory {
+			return "", nil
+		}
+		directory = parent
+	}
+}
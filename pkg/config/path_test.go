@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_resolve(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	nested := filepath.Join(project, "nested", "directory")
+	err := os.MkdirAll(nested, 0700)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	err = os.WriteFile(filepath.Join(project, ProjectConfigFile), []byte{}, 0600)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := []struct {
+		name        string
+		file        string
+		environment string
+		directory   string
+		want        string
+	}{
+		{
+			name:        "should prefer the given file",
+			file:        "/tmp/flag.yaml",
+			environment: "/tmp/environment.yaml",
+			directory:   nested,
+			want:        "/tmp/flag.yaml",
+		},
+		{
+			name:        "should use the environment variable, as no file is given",
+			environment: "/tmp/environment.yaml",
+			directory:   nested,
+			want:        "/tmp/environment.yaml",
+		},
+		{
+			name:      "should find the project config file within a parent directory",
+			directory: nested,
+			want:      filepath.Join(project, ProjectConfigFile),
+		},
+		{
+			name:      "should find the project config file within the working directory",
+			directory: project,
+			want:      filepath.Join(project, ProjectConfigFile),
+		},
+		{
+			name:      "should fall back to the default config file",
+			directory: root,
+			want:      DefaultConfigPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigEnvironmentVariable, tt.environment)
+
+			got, err := resolve(tt.file, tt.directory)
+			if err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.want != got {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,468 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Issue describes a single finding of the config validation
+type Issue struct {
+	File string
+	// Line within the config file, zero if the location is unknown
+	Line int
+	// Path of the offending key, e.g. group.items[0].sources[1]
+	Path    string
+	Message string
+}
+
+// Validator checks the given config and reports all issues, the location of each issue is resolved by its path
+type Validator func(config *Config) []Issue
+
+// sortValues contains all valid values for the selection sort
+var sortValues = []string{"", "asc", "desc", "recent", "frecency"}
+
+// selectorValues contains all valid values for the selector
+var selectorValues = []string{"", SelectorBuiltin, SelectorFzf}
+
+// reported contains all config files, whose issues have already been logged by this process
+var reported sync.Map
+
+// IssuesError is returned by Read, if the config is invalid and strict validation is enabled
+type IssuesError struct {
+	Issues []Issue
+}
+
+func (e *IssuesError) Error() string {
+	return "invalid config:\n" + strings.Join(lo.Map(e.Issues, func(item Issue, _ int) string {
+		return item.String()
+	}), "\n")
+}
+
+func (i Issue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, i.Path, i.Message)
+}
+
+// Validate checks the config for unknown keys, duplicate names, undefined references, invalid
+// sort values and glob patterns, additional validators are executed afterwards
+func (r *Client) Validate(config *Config, validators ...Validator) ([]Issue, error) {
+	data, err := os.ReadFile(r.File)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file, err: '%w'", err)
+	}
+
+	var document yaml.Node
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse config file, err: '%w'", err)
+	}
+
+	locations := map[string]int{}
+	var issues []Issue
+	if len(document.Content) > 0 {
+		issues = walk(document.Content[0], reflect.TypeOf(Config{}), "", locations)
+	}
+
+	validators = append([]Validator{validateGlobal, validateGroups, validateSources, validateContexts}, validators...)
+	for _, validator := range validators {
+		issues = append(issues, validator(config)...)
+	}
+
+	for i := range issues {
+		issues[i].File = r.File
+		if issues[i].Line == 0 {
+			issues[i].Line = locate(locations, issues[i].Path)
+		}
+	}
+
+	return issues, nil
+}
+
+// report validates the config, that has just been read, issues are logged once per process
+// or returned as error, if strict validation is enabled
+// The issues are written to stderr, the config is read before any command output, e.g. json or shell code.
+func (r *Client) report(config *Config) error {
+	log := logger.New().WithWriter(os.Stderr)
+
+	issues, err := r.Validate(config)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	if config.Global.Strict {
+		return &IssuesError{Issues: issues}
+	}
+
+	if _, loaded := reported.LoadOrStore(r.File, true); loaded {
+		return nil
+	}
+	for _, issue := range issues {
+		log.Warn(issue.String())
+	}
+	log.Warn("the config is invalid, run 'kontext config validate' for details")
+	return nil
+}
+
+// walk records the line of each key and reports all keys, that are not part of the given type
+func walk(node *yaml.Node, target reflect.Type, path string, locations map[string]int) []Issue {
+	var issues []Issue
+
+	for target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		if target.Kind() != reflect.Struct {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := join(path, key.Value)
+			locations[childPath] = key.Line
+
+			field, ok := lookupField(target, key.Value)
+			if !ok {
+				issues = append(issues, Issue{
+					Line:    key.Line,
+					Path:    childPath,
+					Message: "unknown key",
+				})
+				continue
+			}
+			issues = append(issues, walk(value, field.Type, childPath, locations)...)
+		}
+	case yaml.SequenceNode:
+		if target.Kind() != reflect.Slice {
+			return nil
+		}
+		for i, item := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			locations[childPath] = item.Line
+			issues = append(issues, walk(item, target.Elem(), childPath, locations)...)
+		}
+	}
+
+	return issues
+}
+
+// lookupField finds the struct field, whose json tag matches the given key
+func lookupField(target reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// locate returns the line of the given path, it falls back to the closest parent, that has a known location
+func locate(locations map[string]int, path string) int {
+	for len(path) > 0 {
+		if line, ok := locations[path]; ok {
+			return line
+		}
+		index := strings.LastIndexAny(path, ".[")
+		if index < 0 {
+			return 0
+		}
+		path = path[:index]
+	}
+	return 0
+}
+
+func join(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func validateGlobal(config *Config) []Issue {
+	if lo.Contains(selectorValues, config.Global.Selector) {
+		return nil
+	}
+	return []Issue{{
+		Path:    "global.selector",
+		Message: fmt.Sprintf("invalid selector '%s', valid values are: %s", config.Global.Selector, strings.Join(selectorValues[1:], ", ")),
+	}}
+}
+
+func validateGroups(config *Config) []Issue {
+	var issues []Issue
+
+	sources := lo.Map(config.Source.Items, func(item SourceItem, _ int) string {
+		return item.Name
+	})
+	groups := lo.Map(config.Group.Items, func(item GroupItem, _ int) string {
+		return item.Name
+	})
+
+	issues = append(issues, validateNames("group.items", groups)...)
+	for i, group := range config.Group.Items {
+		path := fmt.Sprintf("group.items[%d]", i)
+
+		for j, source := range group.Sources {
+			if !lo.Contains(sources, source) {
+				issues = append(issues, Issue{
+					Path:    fmt.Sprintf("%s.sources[%d]", path, j),
+					Message: fmt.Sprintf("source '%s' is not defined", source),
+				})
+			}
+		}
+		for j, name := range group.Groups {
+			if !lo.Contains(groups, name) {
+				issues = append(issues, Issue{
+					Path:    fmt.Sprintf("%s.groups[%d]", path, j),
+					Message: fmt.Sprintf("group '%s' is not defined", name),
+				})
+			}
+		}
+		if cycle := findCycle(config.Group.Items, group.Name); len(cycle) > 0 {
+			issues = append(issues, Issue{
+				Path:    path + ".groups",
+				Message: fmt.Sprintf("group '%s' is part of a cycle: %s", group.Name, strings.Join(cycle, " -> ")),
+			})
+		}
+		for j, source := range group.ExcludeSources {
+			if !lo.Contains(sources, source) {
+				issues = append(issues, Issue{
+					Path:    fmt.Sprintf("%s.excludeSources[%d]", path, j),
+					Message: fmt.Sprintf("source '%s' is not defined", source),
+				})
+			}
+		}
+		for j, pattern := range group.ExcludeContexts {
+			issues = append(issues, validatePattern(fmt.Sprintf("%s.excludeContexts[%d]", path, j), pattern)...)
+		}
+		issues = append(issues, validateSort(path+".context.selection.sort", group.Context.Selection.Sort)...)
+		if group.Context.TTL > 0 && len(group.Context.Safe) == 0 {
+			issues = append(issues, Issue{
+				Path:    path + ".context.ttl",
+				Message: "ttl requires a safe context, set context.safe of the group",
+			})
+		}
+		if _, err := labels.Parse(group.Selector); err != nil {
+			issues = append(issues, Issue{
+				Path:    path + ".selector",
+				Message: fmt.Sprintf("invalid selector '%s', err: '%v'", group.Selector, err),
+			})
+		}
+	}
+
+	issues = append(issues, validateSort("group.selection.sort", config.Group.Selection.Sort)...)
+	selection := config.Group.Selection.Default
+	if len(selection) > 0 && selection != "-" && !lo.Contains(groups, selection) {
+		issues = append(issues, Issue{
+			Path:    "group.selection.default",
+			Message: fmt.Sprintf("group '%s' is not defined", selection),
+		})
+	}
+
+	return issues
+}
+
+func validateSources(config *Config) []Issue {
+	var issues []Issue
+
+	sources := lo.Map(config.Source.Items, func(item SourceItem, _ int) string {
+		return item.Name
+	})
+	issues = append(issues, validateNames("source.items", sources)...)
+
+	for i, source := range config.Source.Items {
+		for j, include := range source.Include {
+			issues = append(issues, validatePattern(fmt.Sprintf("source.items[%d].include[%d]", i, j), include)...)
+		}
+		for j, exclude := range source.Exclude {
+			issues = append(issues, validatePattern(fmt.Sprintf("source.items[%d].exclude[%d]", i, j), exclude)...)
+		}
+		for j, pattern := range source.Contexts.Include {
+			issues = append(issues, validateContextPattern(fmt.Sprintf("source.items[%d].contexts.include[%d]", i, j), pattern)...)
+		}
+		for j, pattern := range source.Contexts.Exclude {
+			issues = append(issues, validateContextPattern(fmt.Sprintf("source.items[%d].contexts.exclude[%d]", i, j), pattern)...)
+		}
+		if len(source.Exec.Command) == 0 && (len(source.Exec.Args) > 0 || len(source.Exec.Env) > 0) {
+			issues = append(issues, Issue{
+				Path:    fmt.Sprintf("source.items[%d].exec.command", i),
+				Message: "command must not be empty",
+			})
+		}
+		for j, env := range source.Exec.Env {
+			if len(env.Name) == 0 {
+				issues = append(issues, Issue{
+					Path:    fmt.Sprintf("source.items[%d].exec.env[%d].name", i, j),
+					Message: "name must not be empty",
+				})
+			}
+		}
+		if _, err := template.New(source.Name).Parse(source.Rename.Template); err != nil {
+			issues = append(issues, Issue{
+				Path:    fmt.Sprintf("source.items[%d].rename.template", i),
+				Message: fmt.Sprintf("invalid template, err: '%v'", err),
+			})
+		}
+	}
+
+	return issues
+}
+
+func validateContexts(config *Config) []Issue {
+	var issues []Issue
+
+	safe := lo.ContainsBy(config.Group.Items, func(group GroupItem) bool {
+		return len(group.Context.Safe) > 0
+	})
+	for i, context := range config.Context.Items {
+		issues = append(issues, validatePattern(fmt.Sprintf("context.items[%d].name", i), context.Name)...)
+		if context.TTL > 0 && !safe {
+			issues = append(issues, Issue{
+				Path:    fmt.Sprintf("context.items[%d].ttl", i),
+				Message: "ttl requires a safe context, but no group sets context.safe",
+			})
+		}
+	}
+	for i, item := range config.Label.Items {
+		issues = append(issues, validatePattern(fmt.Sprintf("label.items[%d].file", i), item.File)...)
+		issues = append(issues, validateLabels(fmt.Sprintf("label.items[%d].labels", i), item.Labels)...)
+	}
+	for i, source := range config.Source.Items {
+		issues = append(issues, validateLabels(fmt.Sprintf("source.items[%d].labels", i), source.Labels)...)
+	}
+
+	return issues
+}
+
+// findCycle returns the path from the given group back to itself through the referenced groups, if there is any
+func findCycle(groups []GroupItem, start string) []string {
+	var walk func(path []string) []string
+	walk = func(path []string) []string {
+		group, ok := lo.Find(groups, func(item GroupItem) bool {
+			return item.Name == path[len(path)-1]
+		})
+		if !ok {
+			return nil
+		}
+		for _, name := range group.Groups {
+			if name == start {
+				return append(path, name)
+			}
+			// cycles, that do not contain the start, are reported by their own groups
+			if lo.Contains(path, name) {
+				continue
+			}
+			if cycle := walk(append(path[:len(path):len(path)], name)); len(cycle) > 0 {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return walk([]string{start})
+}
+
+// validateNames reports all names, that are empty or defined more than once
+func validateNames(path string, names []string) []Issue {
+	var issues []Issue
+	seen := map[string]int{}
+
+	for i, name := range names {
+		namePath := fmt.Sprintf("%s[%d].name", path, i)
+		if len(name) == 0 {
+			issues = append(issues, Issue{
+				Path:    namePath,
+				Message: "name must not be empty",
+			})
+			continue
+		}
+		if first, ok := seen[name]; ok {
+			issues = append(issues, Issue{
+				Path:    namePath,
+				Message: fmt.Sprintf("name '%s' is already defined by %s[%d]", name, path, first),
+			})
+			continue
+		}
+		seen[name] = i
+	}
+
+	return issues
+}
+
+func validateSort(path string, value string) []Issue {
+	if lo.Contains(sortValues, value) {
+		return nil
+	}
+	return []Issue{{
+		Path:    path,
+		Message: fmt.Sprintf("invalid sort '%s', valid values are: %s", value, strings.Join(sortValues[1:], ", ")),
+	}}
+}
+
+func validateLabels(path string, values map[string]string) []Issue {
+	var issues []Issue
+
+	keys := lo.Keys(values)
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := values[key]
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			issues = append(issues, Issue{
+				Path:    path + "." + key,
+				Message: fmt.Sprintf("invalid label key '%s', err: '%s'", key, strings.Join(errs, ", ")),
+			})
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			issues = append(issues, Issue{
+				Path:    path + "." + key,
+				Message: fmt.Sprintf("invalid label value '%s', err: '%s'", value, strings.Join(errs, ", ")),
+			})
+		}
+	}
+	return issues
+}
+
+// validateContextPattern validates the pattern as regular expression, if it has the regex prefix, or as glob pattern
+func validateContextPattern(path string, pattern string) []Issue {
+	expression, ok := strings.CutPrefix(pattern, RegexPrefix)
+	if !ok {
+		return validatePattern(path, pattern)
+	}
+	if _, err := CompileRegex(expression); err != nil {
+		return []Issue{{
+			Path:    path,
+			Message: fmt.Sprintf("invalid regular expression '%s', err: '%v'", expression, err),
+		}}
+	}
+	return nil
+}
+
+func validatePattern(path string, pattern string) []Issue {
+	if doublestar.ValidatePattern(pattern) {
+		return nil
+	}
+	return []Issue{{
+		Path:    path,
+		Message: fmt.Sprintf("invalid glob pattern '%s'", pattern),
+	}}
+}
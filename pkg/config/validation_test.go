@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Validate(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+
+	tests := []struct {
+		name       string
+		file       string
+		validators []Validator
+		want       []Issue
+		wantErr    bool
+	}{
+		{
+			name: "should not report any issue, as the config is valid",
+			file: filepath.Join(caller, "..", "testdata", "02-valid-config-default-values.yaml"),
+			want: nil,
+		},
+		{
+			name: "should report all issues with their location",
+			file: filepath.Join(caller, "..", "testdata", "05-invalid-config.yaml"),
+			validators: []Validator{
+				func(config *Config) []Issue {
+					return []Issue{{Path: "group.items[1].context.default", Message: "custom"}}
+				},
+			},
+			want: []Issue{
+				{Line: 3, Path: "global.colour", Message: "unknown key"},
+				{Line: 4, Path: "global.selector", Message: "invalid selector 'skim', valid values are: builtin, fzf"},
+				{Line: 14, Path: "group.items[1].name", Message: "name 'default' is already defined by group.items[0]"},
+				{Line: 10, Path: "group.items[0].sources[1]", Message: "source 'missing' is not defined"},
+				{Line: 13, Path: "group.items[0].context.selection.sort", Message: "invalid sort 'sideways', valid values are: asc, desc, recent, frecency"},
+				{Line: 17, Path: "group.items[1].selector", Message: "invalid selector 'env in (dev', err: 'unable to parse requirement: found '', expected: ',' or ')''"},
+				{Line: 21, Path: "group.items[2].groups[1]", Message: "group 'unknown' is not defined"},
+				{Line: 19, Path: "group.items[2].groups", Message: "group 'nested' is part of a cycle: nested -> nested"},
+				{Line: 23, Path: "group.items[2].excludeSources[0]", Message: "source 'missing' is not defined"},
+				{Line: 25, Path: "group.items[2].excludeContexts[0]", Message: "invalid glob pattern 'kind-['"},
+				{Line: 27, Path: "group.selection.default", Message: "group 'unknown' is not defined"},
+				{Line: 33, Path: "source.items[0].include[1]", Message: "invalid glob pattern '/tmp/[a'"},
+				{Line: 36, Path: "source.items[0].contexts.include[0]", Message: "invalid regular expression 'prod-(', err: 'error parsing regexp: missing closing ): `prod-(`'"},
+				{Line: 38, Path: "source.items[0].contexts.exclude[0]", Message: "invalid glob pattern 'kind-['"},
+				{Line: 39, Path: "source.items[0].exec.command", Message: "command must not be empty"},
+				{Line: 44, Path: "context.items[0].name", Message: "invalid glob pattern 'kind-['"},
+				{Line: 49, Path: "label.items[0].file", Message: "invalid glob pattern '/tmp/[b'"},
+				{Line: 50, Path: "label.items[0].labels.Env Prod", Message: "invalid label key 'Env Prod', err: 'name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')'"},
+				{Line: 14, Path: "group.items[1].context.default", Message: "custom"},
+			},
+		},
+		{
+			name: "should report all ttls, as there is no safe context",
+			file: filepath.Join(caller, "..", "testdata", "07-invalid-config-ttl.yaml"),
+			want: []Issue{
+				{Line: 5, Path: "group.items[0].context.ttl", Message: "ttl requires a safe context, set context.safe of the group"},
+				{Line: 10, Path: "context.items[0].ttl", Message: "ttl requires a safe context, but no group sets context.safe"},
+			},
+		},
+		{
+			name:    "should throw an error, as the config file does not exist",
+			file:    filepath.Join(caller, "..", "testdata", "missing.yaml"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{File: tt.file}
+			config := &Config{}
+			if !tt.wantErr {
+				var err error
+				config, err = client.Load()
+				if err != nil {
+					t.Fatalf("unexpected error, err: '%v'", err)
+				}
+			}
+
+			got, err := client.Validate(config, tt.validators...)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			for i := range tt.want {
+				tt.want[i].File = tt.file
+			}
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("config.Validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_ReadStrict(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	file := filepath.Join(caller, "..", "testdata", "06-invalid-config-strict.yaml")
+
+	client := &Client{File: file}
+	_, err := client.Read()
+
+	var issuesError *IssuesError
+	if !errors.As(err, &issuesError) {
+		t.Fatalf("expected issues error, got: '%v'", err)
+	}
+	want := []Issue{
+		{File: file, Line: 8, Path: "group.items[0].sources[0]", Message: "source 'missing' is not defined"},
+	}
+	if !cmp.Equal(want, issuesError.Issues) {
+		diff := cmp.Diff(want, issuesError.Issues)
+		t.Errorf("config.Read() mismatch (-want +got):\n%s", diff)
+	}
+}
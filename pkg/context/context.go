@@ -3,11 +3,14 @@ package context
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/orbatschow/kontext/pkg/config"
 	"github.com/orbatschow/kontext/pkg/kubeconfig"
 	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/source"
 	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -15,18 +18,21 @@ type Client struct {
 	Config    *config.Config
 	State     *state.State
 	APIConfig *api.Config
+	// Yes skips the confirmation of protected contexts
+	Yes bool
 }
 
 const (
-	MaxSelectHeight      = 500
 	PreviousContextAlias = "-"
 	SortAsc              = "asc"
 	SortDesc             = "desc"
+	SortRecent           = "recent"
+	SortFrecency         = "frecency"
 )
 
-func New() (*Client, error) {
+func New(configFile string) (*Client, error) {
 	configClient := &config.Client{
-		File: config.DefaultConfigPath,
+		File: configFile,
 	}
 	config, err := configClient.Read()
 	if err != nil {
@@ -88,14 +94,16 @@ func (c *Client) Set(contextName string) error {
 	}
 
 	if len(contextName) == 0 {
-		printer, err := c.buildInteractiveSelectPrinter()
+		result, err := c.selectContext()
 		if err != nil {
 			return err
 		}
-		contextName, err = printer.Show()
-		if err != nil {
-			return err
+
+		// the context has been selected from another group, switch to this group as well
+		if result.Page != c.State.Group.Active {
+			return c.setWithinGroup(result.Page, result.Item)
 		}
+		contextName = result.Item
 	}
 
 	_, ok := c.APIConfig.Contexts[contextName]
@@ -103,10 +111,82 @@ func (c *Client) Set(contextName string) error {
 		return fmt.Errorf("could not find context: '%s'", contextName)
 	}
 
+	err := c.confirm(contextName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
 	c.APIConfig.CurrentContext = contextName
 	c.State.Context.Active = contextName
+	c.State.Context.Activated = &now
 	c.State.Context.History = state.ComputeHistory(c.Config, state.History(contextName), history)
+	c.State.Context.Usage = state.ComputeUsage(contextName, c.State.Context.Usage, now)
+	c.State.Group.Contexts = state.ComputeContexts(c.State.Group.Active, contextName, c.State.Group.Contexts)
 
 	log.Info("switched context", log.Args("context", contextName))
 	return nil
 }
+
+// setWithinGroup switches to the given group and sets the given context afterwards
+// The active group is kept, if the context can not be set.
+func (c *Client) setWithinGroup(groupName string, contextName string) error {
+	log := logger.New()
+
+	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == groupName
+	})
+	if !ok {
+		return fmt.Errorf("could not find group: '%s'", groupName)
+	}
+
+	apiConfig, conflicts, err := source.MergeGroup(c.Config, &group, source.ExecRun)
+	if err != nil {
+		return err
+	}
+	for _, conflict := range conflicts {
+		log.Warn(conflict.String(), log.Args("group", groupName, "hint", "configure source.items[].rename"))
+	}
+
+	// the context is protected by the new group
+	previousGroup, previousAPIConfig := c.State.Group.Active, c.APIConfig
+	c.State.Group.Active = groupName
+	c.APIConfig = apiConfig
+
+	err = c.Set(contextName)
+	if err != nil {
+		c.State.Group.Active = previousGroup
+		c.APIConfig = previousAPIConfig
+		return err
+	}
+	c.State.Group.History = state.ComputeHistory(c.Config, state.History(groupName), c.State.Group.History)
+	c.State.Group.Usage = state.ComputeUsage(groupName, c.State.Group.Usage, time.Now())
+
+	log.Info("switched group", log.Args("group", groupName))
+	return nil
+}
+
+// confirm fails, if the given context is protected and the user did not confirm the switch
+// The active context can always be selected again.
+func (c *Client) confirm(contextName string) error {
+	if c.Yes || contextName == c.State.Context.Active {
+		return nil
+	}
+
+	protected, err := c.Protected(c.APIConfig.Contexts)
+	if err != nil {
+		return err
+	}
+	if !protected[contextName] {
+		return nil
+	}
+
+	confirmed, err := confirm(contextName)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("context '%s' is protected, the switch has not been confirmed", contextName)
+	}
+	return nil
+}
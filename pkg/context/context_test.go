@@ -1,7 +1,9 @@
 package context
 
 import (
+	"path/filepath"
 	"reflect"
+	"runtime"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -252,6 +254,17 @@ func Test_Set(t *testing.T) {
 				t.Errorf("client.Get() apiConfig mismatch (-want +got):\n%s", diff)
 			}
 
+			// the activation timestamp is not deterministic, it only has to be set
+			if !tt.wantErr && client.State.Context.Activated == nil {
+				t.Errorf("expected activation timestamp, got: '%v'", client.State.Context.Activated)
+			}
+			client.State.Context.Activated = nil
+
+			if !tt.wantErr && client.State.Context.Usage[client.State.Context.Active].Count != 1 {
+				t.Errorf("expected a single use, got: '%v'", client.State.Context.Usage)
+			}
+			client.State.Context.Usage = nil
+
 			if !tt.wantErr && !reflect.DeepEqual(tt.want.state, client.State) {
 				diff := cmp.Diff(&tt.want, &client.APIConfig)
 				t.Errorf("client.Get() state mismatch (-want +got):\n%s", diff)
@@ -259,3 +272,104 @@ func Test_Set(t *testing.T) {
 		})
 	}
 }
+
+func Test_setWithinGroup(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+
+	tests := []struct {
+		name        string
+		group       string
+		context     string
+		protected   bool
+		wantGroup   string
+		wantContext string
+		wantErr     bool
+	}{
+		{
+			name:        "should switch the group and the context",
+			group:       "group-b",
+			context:     "kind-local",
+			wantGroup:   "group-b",
+			wantContext: "kind-local",
+		},
+		{
+			name:        "should keep the group, as the context does not exist within the group",
+			group:       "group-b",
+			context:     "kind-missing",
+			wantGroup:   "group-a",
+			wantContext: "kind-a",
+			wantErr:     true,
+		},
+		{
+			name:        "should keep the group, as the protected context has not been confirmed",
+			group:       "group-b",
+			context:     "kind-prod",
+			protected:   true,
+			wantGroup:   "group-a",
+			wantContext: "kind-a",
+			wantErr:     true,
+		},
+		{
+			name:        "should throw an error, as the group does not exist",
+			group:       "missing",
+			context:     "kind-local",
+			wantGroup:   "group-a",
+			wantContext: "kind-a",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confirm = func(string) (bool, error) {
+				return false, nil
+			}
+			t.Cleanup(func() {
+				confirm = promptConfirmation
+			})
+
+			client := Client{
+				Config: &config.Config{
+					State: config.State{
+						History: config.History{Size: state.DefaultMaximumHistorySize},
+					},
+					Group: config.Group{
+						Items: []config.GroupItem{
+							{Name: "group-a"},
+							{Name: "group-b", Sources: []string{"dev"}, Protected: tt.protected},
+						},
+					},
+					Source: config.Source{
+						Items: []config.SourceItem{
+							{Name: "dev", Include: []string{kubeconfigFile}},
+						},
+					},
+				},
+				State: &state.State{
+					Group:   state.Group{Active: "group-a"},
+					Context: state.Context{Active: "kind-a"},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: "kind-a",
+					Contexts:       map[string]*api.Context{"kind-a": {}},
+				},
+			}
+
+			err := client.setWithinGroup(tt.group, tt.context)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantGroup != client.State.Group.Active {
+				t.Errorf("want group: '%s', got: '%s'", tt.wantGroup, client.State.Group.Active)
+			}
+			if tt.wantContext != client.APIConfig.CurrentContext {
+				t.Errorf("want context: '%s', got: '%s'", tt.wantContext, client.APIConfig.CurrentContext)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ErrNoSafeContext is returned, if a protected context exceeded its ttl, but there is no safe context to switch to
+var ErrNoSafeContext = errors.New("there is no safe context to switch to")
+
+// Expire switches to the safe context of the active group, if the active context is protected and
+// has been active for longer than its ttl. It returns the safe context, or an empty string if nothing changed.
+func (c *Client) Expire() (string, error) {
+	active := c.State.Context.Active
+	activated := c.State.Context.Activated
+
+	// the context has been changed outside of kontext, so there is nothing to revert
+	if len(active) == 0 || activated == nil || active != c.APIConfig.CurrentContext {
+		return "", nil
+	}
+
+	group, _ := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == c.State.Group.Active
+	})
+	ttl, err := c.ttl(&group, active)
+	if err != nil {
+		return "", err
+	}
+	if ttl <= 0 || time.Since(*activated) < ttl {
+		return "", nil
+	}
+
+	protected, err := c.Protected(map[string]*api.Context{active: c.APIConfig.Contexts[active]})
+	if err != nil {
+		return "", err
+	}
+	if !protected[active] {
+		return "", nil
+	}
+
+	safe := group.Context.Safe
+	if len(safe) == 0 {
+		return "", fmt.Errorf("context '%s' exceeded its ttl of %s, but group '%s' has no safe context, err: '%w'", active, ttl, group.Name, ErrNoSafeContext)
+	}
+	if _, ok := c.APIConfig.Contexts[safe]; !ok {
+		return "", fmt.Errorf("could not find safe context: '%s', err: '%w'", safe, ErrNoSafeContext)
+	}
+
+	now := time.Now()
+	c.APIConfig.CurrentContext = safe
+	c.State.Context.Active = safe
+	c.State.Context.Activated = &now
+	c.State.Context.History = state.ComputeHistory(c.Config, state.History(safe), c.State.Context.History)
+	c.State.Group.Contexts = state.ComputeContexts(c.State.Group.Active, safe, c.State.Group.Contexts)
+
+	return safe, nil
+}
+
+// ttl returns the ttl of the given context, the first matching context item with a ttl takes precedence over the group
+func (c *Client) ttl(group *config.GroupItem, contextName string) (time.Duration, error) {
+	for _, item := range c.Config.Context.Items {
+		if item.TTL <= 0 {
+			continue
+		}
+		match, err := doublestar.Match(item.Name, contextName)
+		if err != nil {
+			return 0, fmt.Errorf("invalid context pattern '%s', err: '%w'", item.Name, err)
+		}
+		if match {
+			return item.TTL, nil
+		}
+	}
+	return group.Context.TTL, nil
+}
@@ -0,0 +1,158 @@
+package context
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Expire(t *testing.T) {
+	expired := time.Now().Add(-2 * time.Hour)
+	recent := time.Now()
+
+	tests := []struct {
+		name           string
+		group          config.GroupItem
+		contexts       []config.ContextItem
+		active         string
+		currentContext string
+		activated      *time.Time
+		want           string
+		wantErr        bool
+	}{
+		{
+			name: "should switch to the safe context, as the protected context exceeded its ttl",
+			group: config.GroupItem{
+				Name:    "dev",
+				Context: config.Context{TTL: time.Hour, Safe: "kind-dev"},
+			},
+			contexts:       []config.ContextItem{{Name: "*-prod", Protected: true}},
+			active:         "kind-prod",
+			currentContext: "kind-prod",
+			activated:      &expired,
+			want:           "kind-dev",
+		},
+		{
+			name: "should not switch, as the protected context did not exceed its ttl",
+			group: config.GroupItem{
+				Name:    "dev",
+				Context: config.Context{TTL: time.Hour, Safe: "kind-dev"},
+			},
+			contexts:       []config.ContextItem{{Name: "*-prod", Protected: true}},
+			active:         "kind-prod",
+			currentContext: "kind-prod",
+			activated:      &recent,
+		},
+		{
+			name: "should not switch, as the context is not protected",
+			group: config.GroupItem{
+				Name:    "dev",
+				Context: config.Context{TTL: time.Hour, Safe: "kind-dev"},
+			},
+			active:         "kind-prod",
+			currentContext: "kind-prod",
+			activated:      &expired,
+		},
+		{
+			name: "should switch to the safe context, as the ttl of the context item takes precedence",
+			group: config.GroupItem{
+				Name:    "dev",
+				Context: config.Context{TTL: 24 * time.Hour, Safe: "kind-dev"},
+			},
+			contexts:       []config.ContextItem{{Name: "*-prod", Protected: true, TTL: time.Minute}},
+			active:         "kind-prod",
+			currentContext: "kind-prod",
+			activated:      &expired,
+			want:           "kind-dev",
+		},
+		{
+			name: "should not switch, as the context has been changed outside of kontext",
+			group: config.GroupItem{
+				Name:    "dev",
+				Context: config.Context{TTL: time.Hour, Safe: "kind-dev"},
+			},
+			contexts:       []config.ContextItem{{Name: "*-prod", Protected: true}},
+			active:         "kind-prod",
+			currentContext: "kind-local",
+			activated:      &expired,
+		},
+		{
+			name: "should throw an error, as the group has no safe context",
+			group: config.GroupItem{
+				Name:    "dev",
+				Context: config.Context{TTL: time.Hour},
+			},
+			contexts:       []config.ContextItem{{Name: "*-prod", Protected: true}},
+			active:         "kind-prod",
+			currentContext: "kind-prod",
+			activated:      &expired,
+			wantErr:        true,
+		},
+		{
+			name: "should throw an error, as the safe context does not exist",
+			group: config.GroupItem{
+				Name:    "dev",
+				Context: config.Context{TTL: time.Hour, Safe: "missing"},
+			},
+			contexts:       []config.ContextItem{{Name: "*-prod", Protected: true}},
+			active:         "kind-prod",
+			currentContext: "kind-prod",
+			activated:      &expired,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: &config.Config{
+					State: config.State{
+						History: config.History{Size: state.DefaultMaximumHistorySize},
+					},
+					Group: config.Group{
+						Items: []config.GroupItem{tt.group},
+					},
+					Context: config.Contexts{
+						Items: tt.contexts,
+					},
+				},
+				State: &state.State{
+					Group: state.Group{Active: tt.group.Name},
+					Context: state.Context{
+						Active:    tt.active,
+						Activated: tt.activated,
+					},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: tt.currentContext,
+					Contexts: map[string]*api.Context{
+						"kind-dev":   {},
+						"kind-local": {},
+						"kind-prod":  {},
+					},
+				},
+			}
+
+			got, err := client.Expire()
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			// the gc command warns about a missing safe context, instead of failing silently
+			if tt.wantErr && !errors.Is(err, ErrNoSafeContext) {
+				t.Errorf("expected error '%v', got: '%v'", ErrNoSafeContext, err)
+			}
+
+			if tt.want != got {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got)
+			}
+
+			if len(tt.want) > 0 && client.APIConfig.CurrentContext != tt.want {
+				t.Errorf("want current context: '%s', got: '%s'", tt.want, client.APIConfig.CurrentContext)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package context
+
+import (
+	"sort"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Item is the machine-readable representation of a context, its fields are part of the public output schema
+type Item struct {
+	Name      string `json:"name"`
+	Active    bool   `json:"active"`
+	Cluster   string `json:"cluster"`
+	User      string `json:"user"`
+	Namespace string `json:"namespace"`
+	// Protected contexts require a confirmation, before they become active
+	Protected bool   `json:"protected"`
+	Group     string `json:"group"`
+	// Files contains all source files of the active group, that define the context, the first file takes precedence
+	Files []string `json:"files"`
+	// Labels are attached by the sources, the label items and `kontext label context`
+	Labels map[string]string `json:"labels"`
+}
+
+// BuildItems converts the given contexts into items, that are sorted by name
+func (c *Client) BuildItems(contexts map[string]*api.Context) ([]Item, error) {
+	buffer := []Item{}
+
+	kubeconfigs, err := c.loadGroup(c.State.Group.Active)
+	if err != nil {
+		return nil, err
+	}
+	files, contextLabels, err := computeSources(c.Config, kubeconfigs)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := c.Protected(contexts)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, context := range contexts {
+		buffer = append(buffer, Item{
+			Name:      name,
+			Active:    name == c.State.Context.Active,
+			Cluster:   context.Cluster,
+			User:      context.AuthInfo,
+			Namespace: context.Namespace,
+			Protected: protected[name],
+			Group:     c.State.Group.Active,
+			Files:     lo.Ternary(files[name] == nil, []string{}, files[name]),
+			Labels:    lo.Ternary(contextLabels[name] == nil, map[string]string{}, contextLabels[name]),
+		})
+	}
+
+	sort.Slice(buffer, func(i, j int) bool {
+		return buffer[i].Name < buffer[j].Name
+	})
+	return buffer, nil
+}
+
+// loadGroup loads the kubeconfigs of the given group, a group, that does not exist, has no kubeconfigs
+func (c *Client) loadGroup(groupName string) ([]source.Kubeconfig, error) {
+	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == groupName
+	})
+	if !ok {
+		return nil, nil
+	}
+
+	return source.LoadGroup(c.Config, &group, source.ExecCached)
+}
+
+// computeSources maps each context name to the source files of the given kubeconfigs, that define the context,
+// and to its labels
+func computeSources(currentConfig *config.Config, kubeconfigs []source.Kubeconfig) (map[string][]string, map[string]labels.Set, error) {
+	buffer := map[string][]string{}
+
+	for _, item := range kubeconfigs {
+		for name := range item.APIConfig.Contexts {
+			buffer[name] = append(buffer[name], item.File)
+		}
+	}
+
+	contextLabels, err := source.ComputeLabels(currentConfig, kubeconfigs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buffer, contextLabels, nil
+}
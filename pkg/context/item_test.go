@@ -0,0 +1,98 @@
+package context
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_BuildItems(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+
+	currentConfig := &config.Config{
+		Group: config.Group{
+			Items: []config.GroupItem{
+				{Name: "dev", Sources: []string{"dev"}},
+			},
+		},
+		Source: config.Source{
+			Items: []config.SourceItem{
+				{Name: "dev", Include: []string{kubeconfigFile}, Labels: map[string]string{"env": "dev"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		state    *state.State
+		contexts map[string]*api.Context
+		want     []Item
+		wantErr  bool
+	}{
+		{
+			name: "should build the items sorted by name, including the files of the active group",
+			state: &state.State{
+				Group: state.Group{
+					Active: "dev",
+				},
+				Context: state.Context{
+					Active: "kind-dev",
+				},
+			},
+			contexts: map[string]*api.Context{
+				"kind-prod": {Cluster: "kind-prod", AuthInfo: "kind-prod"},
+				"kind-dev":  {Cluster: "kind-dev", AuthInfo: "kind-dev", Namespace: "kube-system"},
+				"missing":   {},
+			},
+			want: []Item{
+				{Name: "kind-dev", Active: true, Cluster: "kind-dev", User: "kind-dev", Namespace: "kube-system", Group: "dev", Files: []string{kubeconfigFile}, Labels: map[string]string{"env": "dev"}},
+				{Name: "kind-prod", Cluster: "kind-prod", User: "kind-prod", Group: "dev", Files: []string{kubeconfigFile}, Labels: map[string]string{"env": "dev"}},
+				{Name: "missing", Group: "dev", Files: []string{}, Labels: map[string]string{}},
+			},
+		},
+		{
+			name:  "should build the items without files, as there is no active group",
+			state: &state.State{},
+			contexts: map[string]*api.Context{
+				"kind-dev": {Cluster: "kind-dev", AuthInfo: "kind-dev"},
+			},
+			want: []Item{
+				{Name: "kind-dev", Cluster: "kind-dev", User: "kind-dev", Files: []string{}, Labels: map[string]string{}},
+			},
+		},
+		{
+			name:     "should return an empty list, as there are no contexts",
+			state:    &state.State{},
+			contexts: map[string]*api.Context{},
+			want:     []Item{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: currentConfig,
+				State:  tt.state,
+			}
+
+			got, err := client.BuildItems(tt.contexts)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("context.BuildItems() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// GroupContextSeparator separates the group from the context, e.g. dev/kind-dev
+const GroupContextSeparator = "/"
+
+// Minify returns a kubeconfig, that only contains a single context, its cluster and its user.
+// The target is either a context of the current kubeconfig or a context of any group, written as group/context.
+// Contexts of the current kubeconfig take precedence, as context names may contain the separator as well.
+func (c *Client) Minify(target string) (*api.Config, error) {
+	if len(target) == 0 {
+		return nil, fmt.Errorf("given context name is empty")
+	}
+
+	if _, ok := c.APIConfig.Contexts[target]; ok {
+		apiConfig, err := kubeconfig.Minify(c.APIConfig, target)
+		if err != nil {
+			return nil, err
+		}
+		// the minified kubeconfig is written to another directory, so relative paths have to be resolved first
+		for _, cluster := range apiConfig.Clusters {
+			cluster.LocationOfOrigin = lo.Ternary(len(cluster.LocationOfOrigin) == 0, c.Config.Global.Kubeconfig, cluster.LocationOfOrigin)
+		}
+		for _, authInfo := range apiConfig.AuthInfos {
+			authInfo.LocationOfOrigin = lo.Ternary(len(authInfo.LocationOfOrigin) == 0, c.Config.Global.Kubeconfig, authInfo.LocationOfOrigin)
+		}
+		err = clientcmd.ResolveLocalPaths(apiConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve local paths, err: '%w'", err)
+		}
+		return apiConfig, nil
+	}
+
+	groupName, contextName, ok := strings.Cut(target, GroupContextSeparator)
+	if !ok {
+		return nil, fmt.Errorf("could not find context '%s'", target)
+	}
+	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == groupName
+	})
+	if !ok {
+		return nil, fmt.Errorf("could not find context '%s' or group '%s'", target, groupName)
+	}
+
+	// sources resolve their local paths while loading
+	apiConfig, _, err := source.MergeGroup(c.Config, &group, source.ExecRun)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := apiConfig.Contexts[contextName]; !ok {
+		return nil, fmt.Errorf("could not find context '%s' within group '%s'", contextName, groupName)
+	}
+	return kubeconfig.Minify(apiConfig, contextName)
+}
@@ -0,0 +1,128 @@
+package context
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/orbatschow/kontext/pkg/config"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Minify(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+	kubeconfigDirectory := t.TempDir()
+
+	client := Client{
+		Config: &config.Config{
+			Global: config.Global{
+				Kubeconfig: filepath.Join(kubeconfigDirectory, "kubeconfig.yaml"),
+			},
+			Group: config.Group{
+				Items: []config.GroupItem{{Name: "dev", Sources: []string{"dev"}}},
+			},
+			Source: config.Source{
+				Items: []config.SourceItem{{Name: "dev", Include: []string{kubeconfigFile}}},
+			},
+		},
+		APIConfig: &api.Config{
+			CurrentContext: "local",
+			Clusters: map[string]*api.Cluster{
+				"local": {Server: "https://127.0.0.1", CertificateAuthority: "ca.crt"},
+			},
+			AuthInfos: map[string]*api.AuthInfo{
+				"local": {Token: "local"},
+			},
+			Contexts: map[string]*api.Context{
+				"local":            {Cluster: "local", AuthInfo: "local"},
+				"cloud/kind-local": {Cluster: "local", AuthInfo: "local"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		want    *api.Config
+		wantErr bool
+	}{
+		{
+			name:   "should minify a context of the current kubeconfig and resolve its relative paths",
+			target: "local",
+			want: &api.Config{
+				CurrentContext: "local",
+				Clusters: map[string]*api.Cluster{
+					"local": {Server: "https://127.0.0.1", CertificateAuthority: filepath.Join(kubeconfigDirectory, "ca.crt")},
+				},
+				AuthInfos: map[string]*api.AuthInfo{"local": {Token: "local"}},
+				Contexts:  map[string]*api.Context{"local": {Cluster: "local", AuthInfo: "local"}},
+			},
+		},
+		{
+			name:   "should prefer a context of the current kubeconfig, that contains the separator",
+			target: "cloud/kind-local",
+			want: &api.Config{
+				CurrentContext: "cloud/kind-local",
+				Clusters: map[string]*api.Cluster{
+					"local": {Server: "https://127.0.0.1", CertificateAuthority: filepath.Join(kubeconfigDirectory, "ca.crt")},
+				},
+				AuthInfos: map[string]*api.AuthInfo{"local": {Token: "local"}},
+				Contexts:  map[string]*api.Context{"cloud/kind-local": {Cluster: "local", AuthInfo: "local"}},
+			},
+		},
+		{
+			name:   "should minify a context of the given group",
+			target: "dev/kind-prod",
+			want: &api.Config{
+				CurrentContext: "kind-prod",
+				Clusters: map[string]*api.Cluster{
+					"kind-prod": {Server: "https://127.0.0.1:6445"},
+				},
+				AuthInfos: map[string]*api.AuthInfo{"kind-prod": {Token: "prod"}},
+				Contexts:  map[string]*api.Context{"kind-prod": {Cluster: "kind-prod", AuthInfo: "kind-prod"}},
+			},
+		},
+		{
+			name:    "should throw an error, as the context does not exist",
+			target:  "kind-prod",
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the group does not exist",
+			target:  "prod/kind-prod",
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the context does not exist within the group",
+			target:  "dev/local",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.Minify(tt.target)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			// the origin of each entry is not part of the kubeconfig
+			options := cmp.Options{
+				cmp.FilterPath(func(path cmp.Path) bool {
+					return path.Last().String() == ".LocationOfOrigin"
+				}, cmp.Ignore()),
+				cmpopts.EquateEmpty(),
+			}
+			if !tt.wantErr && !cmp.Equal(tt.want, got, options) {
+				diff := cmp.Diff(tt.want, got, options)
+				t.Errorf("context.Minify() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
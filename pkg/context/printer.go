@@ -2,14 +2,17 @@ package context
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
-func (c *Client) BuildTablePrinter(contexts map[string]*api.Context) *pterm.TablePrinter {
+// BuildTablePrinter renders the given contexts, protected contexts are marked with an exclamation mark
+func (c *Client) BuildTablePrinter(contexts map[string]*api.Context, protected map[string]bool) *pterm.TablePrinter {
 	table := pterm.TableData{
-		{"Active", "Name", "Cluster", "AuthInfo"},
+		{"Active", "Name", "Cluster", "AuthInfo", "Namespace", "Protected"},
 	}
 
 	// sort table data ascending
@@ -25,8 +28,33 @@ func (c *Client) BuildTablePrinter(contexts map[string]*api.Context) *pterm.Tabl
 			active = "*"
 		}
 		table = append(table, []string{
-			active, key, contexts[key].Cluster, contexts[key].AuthInfo,
+			active, key, contexts[key].Cluster, contexts[key].AuthInfo, contexts[key].Namespace, protectedMarker(protected[key]),
 		})
 	}
 	return pterm.DefaultTable.WithHasHeader().WithData(table)
 }
+
+func (c *Client) BuildWideTablePrinter(items ...Item) *pterm.TablePrinter {
+	table := pterm.TableData{
+		{"Active", "Name", "Cluster", "AuthInfo", "Namespace", "Protected", "Group", "File(s)", "Labels"},
+	}
+
+	for _, item := range items {
+		active := ""
+		if item.Active {
+			active = "*"
+		}
+		table = append(table, []string{
+			active, item.Name, item.Cluster, item.User, item.Namespace, protectedMarker(item.Protected), item.Group, strings.Join(item.Files, "\n"),
+			labels.Set(item.Labels).String(),
+		})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(table)
+}
+
+func protectedMarker(protected bool) string {
+	if protected {
+		return "!"
+	}
+	return ""
+}
@@ -12,8 +12,9 @@ import (
 
 func Test_BuildTablePrinter(t *testing.T) {
 	type args struct {
-		State    *state.State
-		Contexts map[string]*api.Context
+		State     *state.State
+		Contexts  map[string]*api.Context
+		Protected map[string]bool
 	}
 	tests := []struct {
 		name    string
@@ -22,7 +23,7 @@ func Test_BuildTablePrinter(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "should print two contexts, one of them being active",
+			name: "should print two contexts, one of them being active and one of them being protected",
 			args: args{
 				State: &state.State{
 					Context: state.Context{
@@ -31,7 +32,10 @@ func Test_BuildTablePrinter(t *testing.T) {
 				},
 				Contexts: map[string]*api.Context{
 					"kind":  {},
-					"local": {},
+					"local": {Namespace: "kube-system"},
+				},
+				Protected: map[string]bool{
+					"kind": true,
 				},
 			},
 			want: &pterm.TablePrinter{
@@ -55,9 +59,9 @@ func Test_BuildTablePrinter(t *testing.T) {
 					pterm.FgGray,
 				},
 				Data: pterm.TableData{
-					[]string{"Active", "Name", "Cluster", "AuthInfo"},
-					[]string{"", "kind", "", ""},
-					[]string{"*", "local", "", ""},
+					[]string{"Active", "Name", "Cluster", "AuthInfo", "Namespace", "Protected"},
+					[]string{"", "kind", "", "", "", "!"},
+					[]string{"*", "local", "", "", "kube-system", ""},
 				},
 				Boxed:          false,
 				LeftAlignment:  true,
@@ -96,9 +100,9 @@ func Test_BuildTablePrinter(t *testing.T) {
 					pterm.FgGray,
 				},
 				Data: pterm.TableData{
-					[]string{"Active", "Name", "Cluster", "AuthInfo"},
-					[]string{"", "kind", "", ""},
-					[]string{"", "local", "", ""},
+					[]string{"Active", "Name", "Cluster", "AuthInfo", "Namespace", "Protected"},
+					[]string{"", "kind", "", "", "", ""},
+					[]string{"", "local", "", "", "", ""},
 				},
 				Boxed:          false,
 				LeftAlignment:  true,
@@ -114,7 +118,7 @@ func Test_BuildTablePrinter(t *testing.T) {
 				State: tt.args.State,
 			}
 
-			got := client.BuildTablePrinter(tt.args.Contexts)
+			got := client.BuildTablePrinter(tt.args.Contexts, tt.args.Protected)
 			options := cmpopts.IgnoreUnexported(pterm.InteractiveSelectPrinter{})
 			if !cmp.Equal(&tt.want, &got, options) {
 				diff := cmp.Diff(tt.want, got, options)
@@ -0,0 +1,141 @@
+package context
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/pterm/pterm"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ProtectedMarker is appended to protected contexts within the interactive selection
+const ProtectedMarker = " [protected]"
+
+// confirm asks the user to confirm the switch to the given protected context
+var confirm = promptConfirmation
+
+// Protected returns all given contexts, that require a confirmation before they become active
+// A context is protected by the active group, by a matching context item or by any source of the active group,
+// that defines it.
+func (c *Client) Protected(contexts map[string]*api.Context) (map[string]bool, error) {
+	return c.protected(c.State.Group.Active, contexts)
+}
+
+// protected returns all given contexts, that are protected within the given group
+func (c *Client) protected(groupName string, contexts map[string]*api.Context) (map[string]bool, error) {
+	buffer := map[string]bool{}
+
+	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == groupName
+	})
+
+	for name := range contexts {
+		if ok && group.Protected {
+			buffer[name] = true
+			continue
+		}
+		for _, item := range c.Config.Context.Items {
+			if !item.Protected {
+				continue
+			}
+			match, err := doublestar.Match(item.Name, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid context pattern '%s', err: '%w'", item.Name, err)
+			}
+			if match {
+				buffer[name] = true
+				break
+			}
+		}
+	}
+
+	if !ok || group.Protected {
+		return buffer, nil
+	}
+
+	// only protected sources have to be read, to find the contexts they define
+	sources, err := source.GroupSources(c.Config, &group)
+	if err != nil {
+		return nil, err
+	}
+	for _, sourceName := range sources {
+		sourceMatch, ok := lo.Find(c.Config.Source.Items, func(item config.SourceItem) bool {
+			return item.Name == sourceName && item.Protected
+		})
+		if !ok {
+			continue
+		}
+		kubeconfigs, err := source.LoadSource(c.Config, &sourceMatch, source.ExecCached)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range kubeconfigs {
+			for name := range item.APIConfig.Contexts {
+				if _, ok := contexts[name]; ok {
+					buffer[name] = true
+				}
+			}
+		}
+	}
+
+	// contexts of referenced groups are protected, if the referenced group protects them
+	for _, groupName := range group.Groups {
+		protected, err := c.protectedByGroup(groupName, contexts)
+		if err != nil {
+			return nil, err
+		}
+		for name := range protected {
+			buffer[name] = true
+		}
+	}
+
+	return buffer, nil
+}
+
+// protectedByGroup returns all given contexts, that are provided and protected by the given referenced group
+func (c *Client) protectedByGroup(groupName string, contexts map[string]*api.Context) (map[string]bool, error) {
+	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == groupName
+	})
+	if !ok {
+		return map[string]bool{}, nil
+	}
+	if !group.Protected {
+		return c.protected(groupName, contexts)
+	}
+
+	buffer := map[string]bool{}
+	kubeconfigs, err := source.LoadGroup(c.Config, &group, source.ExecCached)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range kubeconfigs {
+		for name := range item.APIConfig.Contexts {
+			if _, ok := contexts[name]; ok {
+				buffer[name] = true
+			}
+		}
+	}
+
+	return buffer, nil
+}
+
+// promptConfirmation requires the user to type the name of the context, a plain line from stdin is
+// accepted as well, so scripts can pipe the confirmation
+func promptConfirmation(contextName string) (bool, error) {
+	pterm.Fprint(os.Stderr, pterm.Warning.Sprintf("context '%s' is protected, type its name to confirm: ", contextName))
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("could not read confirmation, err: '%w'", err)
+	}
+	return strings.TrimSpace(line) == contextName, nil
+}
@@ -0,0 +1,214 @@
+package context
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Protected(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+
+	contexts := map[string]*api.Context{
+		"kind-dev":  {},
+		"kind-prod": {},
+		"local":     {},
+	}
+
+	tests := []struct {
+		name    string
+		config  *config.Config
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "should protect all contexts of the active group",
+			config: &config.Config{
+				Group: config.Group{
+					Items: []config.GroupItem{{Name: "dev", Protected: true}},
+				},
+			},
+			want: map[string]bool{"kind-dev": true, "kind-prod": true, "local": true},
+		},
+		{
+			name: "should protect all contexts, that match a protected context item",
+			config: &config.Config{
+				Group: config.Group{
+					Items: []config.GroupItem{{Name: "dev"}},
+				},
+				Context: config.Contexts{
+					Items: []config.ContextItem{
+						{Name: "*-prod", Protected: true},
+						{Name: "local"},
+					},
+				},
+			},
+			want: map[string]bool{"kind-prod": true},
+		},
+		{
+			name: "should protect all contexts, that are defined by a protected source of the active group",
+			config: &config.Config{
+				Group: config.Group{
+					Items: []config.GroupItem{{Name: "dev", Sources: []string{"dev"}}},
+				},
+				Source: config.Source{
+					Items: []config.SourceItem{{Name: "dev", Include: []string{kubeconfigFile}, Protected: true}},
+				},
+			},
+			want: map[string]bool{"kind-dev": true, "kind-prod": true},
+		},
+		{
+			name: "should protect all contexts, that are provided by a protected group, that is referred by the active group",
+			config: &config.Config{
+				Group: config.Group{
+					Items: []config.GroupItem{
+						{Name: "dev", Groups: []string{"prod"}},
+						{Name: "prod", Sources: []string{"dev"}, ExcludeContexts: []string{"kind-dev"}, Protected: true},
+					},
+				},
+				Source: config.Source{
+					Items: []config.SourceItem{{Name: "dev", Include: []string{kubeconfigFile}}},
+				},
+			},
+			want: map[string]bool{"kind-prod": true},
+		},
+		{
+			name: "should throw an error, as the referenced groups contain a cycle",
+			config: &config.Config{
+				Group: config.Group{
+					Items: []config.GroupItem{{Name: "dev", Groups: []string{"dev"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as the context pattern is invalid",
+			config: &config.Config{
+				Context: config.Contexts{
+					Items: []config.ContextItem{{Name: "kind-[", Protected: true}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: tt.config,
+				State: &state.State{
+					Group: state.Group{Active: "dev"},
+				},
+			}
+
+			got, err := client.Protected(contexts)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("context.Protected() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Set_Protected(t *testing.T) {
+	tests := []struct {
+		name        string
+		contextName string
+		yes         bool
+		confirmed   bool
+		wantPrompt  bool
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "should switch to the protected context, as the switch has been confirmed",
+			contextName: "kind-prod",
+			confirmed:   true,
+			wantPrompt:  true,
+			want:        "kind-prod",
+		},
+		{
+			name:        "should throw an error, as the switch has not been confirmed",
+			contextName: "kind-prod",
+			wantPrompt:  true,
+			want:        "kind-dev",
+			wantErr:     true,
+		},
+		{
+			name:        "should switch to the protected context without confirmation, as yes is given",
+			contextName: "kind-prod",
+			yes:         true,
+			want:        "kind-prod",
+		},
+		{
+			name:        "should switch to the unprotected context without confirmation",
+			contextName: "kind-local",
+			want:        "kind-local",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompted := false
+			confirm = func(contextName string) (bool, error) {
+				prompted = true
+				return tt.confirmed, nil
+			}
+			t.Cleanup(func() {
+				confirm = promptConfirmation
+			})
+
+			client := Client{
+				Config: &config.Config{
+					State: config.State{
+						History: config.History{Size: state.DefaultMaximumHistorySize},
+					},
+					Context: config.Contexts{
+						Items: []config.ContextItem{{Name: "*-prod", Protected: true}},
+					},
+				},
+				State: &state.State{
+					Context: state.Context{Active: "kind-dev"},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: "kind-dev",
+					Contexts: map[string]*api.Context{
+						"kind-dev":   {},
+						"kind-local": {},
+						"kind-prod":  {},
+					},
+				},
+				Yes: tt.yes,
+			}
+
+			err := client.Set(tt.contextName)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantPrompt != prompted {
+				t.Errorf("want prompt: '%t', got: '%t'", tt.wantPrompt, prompted)
+			}
+
+			if tt.want != client.APIConfig.CurrentContext {
+				t.Errorf("want: '%s', got: '%s'", tt.want, client.APIConfig.CurrentContext)
+			}
+		})
+	}
+}
@@ -3,57 +3,191 @@ package context
 import (
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/orbatschow/kontext/pkg/config"
-	"github.com/pterm/pterm"
+	"github.com/orbatschow/kontext/pkg/finder"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/orbatschow/kontext/pkg/state"
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
-// start an interactive context selection
-func (c *Client) buildInteractiveSelectPrinter() (*pterm.InteractiveSelectPrinter, error) {
-	// compute all selection options
-	var keys []string
-	for k := range c.APIConfig.Contexts {
-		keys = append(keys, k)
+// selectContext starts an interactive context selection with fzf, if it is configured and installed
+// The builtin finder is used otherwise.
+func (c *Client) selectContext() (*finder.Result, error) {
+	if fzf, ok := finder.NewFzf(c.Config, "context"); ok {
+		page, err := c.buildPage(c.State.Group.Active)
+		if err != nil {
+			return nil, err
+		}
+		return fzf.Show(page)
 	}
 
-	// get the active group
-	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+	selector, err := c.buildFinder()
+	if err != nil {
+		return nil, err
+	}
+	return selector.Show(c.State.Group.Active)
+}
+
+// start an interactive context selection, each group is a page of the finder
+func (c *Client) buildFinder() (*finder.Finder, error) {
+	// the active group is required, to compute the defaults of the selection
+	_, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
 		return item.Name == c.State.Group.Active
 	})
 	if !ok {
 		return nil, fmt.Errorf("could not find default selection context: '%s'", c.State.Group.Active)
 	}
 
-	// sort the selection
+	// sort the groups, just like the group selection does
+	var pages []string
+	for _, value := range c.Config.Group.Items {
+		pages = append(pages, value.Name)
+	}
+	switch c.Config.Group.Selection.Sort {
+	case SortAsc:
+		sort.Strings(pages)
+	case SortDesc:
+		sort.Sort(sort.Reverse(sort.StringSlice(pages)))
+	case SortRecent:
+		sort.Strings(pages)
+		state.SortRecent(pages, c.State.Group.Usage)
+	case SortFrecency:
+		sort.Strings(pages)
+		state.SortFrecency(pages, c.State.Group.Usage, time.Now())
+	}
+
+	return &finder.Finder{
+		PageTitle: "group",
+		Pages:     pages,
+		Load:      c.buildPage,
+	}, nil
+}
+
+// buildPage computes all selection options of the given group
+func (c *Client) buildPage(groupName string) (*finder.Page, error) {
+	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == groupName
+	})
+	if !ok {
+		return nil, fmt.Errorf("could not find group: '%s'", groupName)
+	}
+
+	// the group is loaded once, its kubeconfigs provide the source files and labels of all items
+	kubeconfigs, err := source.LoadGroup(c.Config, &group, source.ExecCached)
+	if err != nil {
+		return nil, err
+	}
+
+	// the active group is already merged, all other groups are merged on demand
+	apiConfig := c.APIConfig
+	if groupName != c.State.Group.Active {
+		apiConfig, _ = source.Merge(kubeconfigs)
+	}
+
+	var keys []string
+	for k := range apiConfig.Contexts {
+		keys = append(keys, k)
+	}
+
+	// sort the selection, the recently used contexts are listed first, unless the selection is sorted by use
 	switch group.Context.Selection.Sort {
 	case SortAsc:
 		sort.Strings(keys)
+		keys = c.floatRecent(keys)
 	case SortDesc:
 		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+		keys = c.floatRecent(keys)
+	case SortRecent:
+		sort.Strings(keys)
+		state.SortRecent(keys, c.State.Context.Usage)
+	case SortFrecency:
+		sort.Strings(keys)
+		state.SortFrecency(keys, c.State.Context.Usage, time.Now())
 	default:
 		sort.Strings(keys)
+		keys = c.floatRecent(keys)
+	}
+
+	protected, err := c.protected(groupName, apiConfig.Contexts)
+	if err != nil {
+		return nil, err
+	}
+	files, contextLabels, err := computeSources(c.Config, kubeconfigs)
+	if err != nil {
+		return nil, err
 	}
 
-	selector := pterm.DefaultInteractiveSelect.
-		WithMaxHeight(MaxSelectHeight).
-		WithOptions(keys)
+	page := &finder.Page{
+		Name: groupName,
+		Items: lo.Map(keys, func(name string, _ int) finder.Item {
+			return buildFinderItem(apiConfig, name, protected[name], files[name], contextLabels[name])
+		}),
+	}
 
 	// check if there are defaults for the selection and set them accordingly
 	switch group.Context.Selection.Default {
-	// if the default is empty, return without setting default option
+	// if the default is empty, the first option is selected
 	case "":
-		return selector, nil
 	// if the default select is "-", set the current context as the default option
 	case "-":
-		return selector.WithDefaultOption(c.State.Context.Active), nil
+		page.Default = c.State.Context.Active
 	// search for the given default selection context
 	default:
-		// get the default selection context
-		_, ok := c.APIConfig.Contexts[group.Context.Selection.Default]
+		_, ok := apiConfig.Contexts[group.Context.Selection.Default]
 		if !ok {
 			return nil, fmt.Errorf("could not find default selection context: '%s'", group.Context.Selection.Default)
 		}
-		return selector.WithDefaultOption(group.Context.Selection.Default), nil
+		page.Default = group.Context.Selection.Default
+	}
+
+	return page, nil
+}
+
+// floatRecent moves all contexts of the history to the top of the given keys, the most recent context comes first
+func (c *Client) floatRecent(keys []string) []string {
+	var recent []string
+	history := c.State.Context.History
+	for i := len(history) - 1; i >= 0; i-- {
+		name := string(history[i])
+		if lo.Contains(keys, name) && !lo.Contains(recent, name) {
+			recent = append(recent, name)
+		}
+	}
+
+	return append(recent, lo.Without(keys, recent...)...)
+}
+
+// buildFinderItem matches the context by its cluster, user, namespace, source files and labels and previews its details
+func buildFinderItem(apiConfig *api.Config, name string, protected bool, files []string, contextLabels labels.Set) finder.Item {
+	context := apiConfig.Contexts[name]
+	if context == nil {
+		context = &api.Context{}
+	}
+
+	cluster := context.Cluster
+	if value, ok := apiConfig.Clusters[context.Cluster]; ok && value != nil && len(value.Server) > 0 {
+		cluster += " (" + value.Server + ")"
+	}
+
+	preview := []string{
+		fmt.Sprintf("%-10s %s", "context:", name),
+		fmt.Sprintf("%-10s %s", "cluster:", cluster),
+		fmt.Sprintf("%-10s %s", "user:", context.AuthInfo),
+		fmt.Sprintf("%-10s %s", "namespace:", context.Namespace),
+		fmt.Sprintf("%-10s %t", "protected:", protected),
+		fmt.Sprintf("%-10s %s", "files:", strings.Join(files, ", ")),
+		fmt.Sprintf("%-10s %s", "labels:", contextLabels.String()),
+	}
+
+	return finder.Item{
+		Name:    name,
+		Label:   lo.Ternary(protected, name+ProtectedMarker, name),
+		Fields:  append([]string{context.Cluster, context.AuthInfo, context.Namespace, contextLabels.String()}, files...),
+		Preview: strings.Join(preview, "\n"),
 	}
 }
@@ -1,332 +1,277 @@
 package context
 
 import (
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/finder"
 	"github.com/orbatschow/kontext/pkg/state"
-	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
-func Test_buildInteractiveSelectPrinter(t *testing.T) {
+func Test_buildFinder(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		group   config.Group
+		usage   map[string]state.Usage
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "should return a finder, that keeps the order of the groups by default",
+			group: config.Group{
+				Items: []config.GroupItem{{Name: "group-b"}, {Name: "group-a"}},
+			},
+			want: []string{"group-b", "group-a"},
+		},
+		{
+			name: "should return a finder, that sorts the groups descending",
+			group: config.Group{
+				Items:     []config.GroupItem{{Name: "group-a"}, {Name: "group-b"}},
+				Selection: config.Selection{Sort: SortDesc},
+			},
+			want: []string{"group-b", "group-a"},
+		},
+		{
+			name: "should return a finder, that sorts the groups by their last use",
+			group: config.Group{
+				Items:     []config.GroupItem{{Name: "group-a"}, {Name: "group-b"}, {Name: "group-c"}},
+				Selection: config.Selection{Sort: SortRecent},
+			},
+			usage: map[string]state.Usage{
+				"group-a": {Count: 10, Last: now.Add(-time.Hour)},
+				"group-c": {Count: 1, Last: now},
+			},
+			want: []string{"group-c", "group-a", "group-b"},
+		},
+		{
+			name: "should return a finder, that sorts the groups by their frecency",
+			group: config.Group{
+				Items:     []config.GroupItem{{Name: "group-a"}, {Name: "group-b"}, {Name: "group-c"}},
+				Selection: config.Selection{Sort: SortFrecency},
+			},
+			usage: map[string]state.Usage{
+				"group-a": {Count: 10, Last: now.Add(-time.Hour)},
+				"group-c": {Count: 1, Last: now},
+			},
+			want: []string{"group-a", "group-c", "group-b"},
+		},
+		{
+			name: "should return an error, as the active group does not exist",
+			group: config.Group{
+				Items: []config.GroupItem{{Name: "group-b"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: &config.Config{Group: tt.group},
+				State: &state.State{
+					Group: state.Group{Active: "group-a", Usage: tt.usage},
+				},
+				APIConfig: &api.Config{},
+			}
+
+			got, err := client.buildFinder()
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if got != nil && !cmp.Equal(tt.want, got.Pages) {
+				diff := cmp.Diff(tt.want, got.Pages)
+				t.Errorf("context.buildFinder() pages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_buildPage(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+
+	contexts := map[string]*api.Context{
+		"kind-b": nil,
+		"kind-c": nil,
+		"kind-a": nil,
+	}
+
 	type args struct {
-		Config    *config.Config
+		Context   config.Context
+		Contexts  []config.ContextItem
 		APIConfig *api.Config
 		State     *state.State
+		Group     string
 	}
 	tests := []struct {
-		name    string
-		args    args
-		want    *pterm.InteractiveSelectPrinter
-		wantErr bool
+		name        string
+		args        args
+		want        []string
+		wantDefault string
+		wantErr     bool
 	}{
 		{
-			name: "should return a printer, that sorts the given contexts by default (ascending)",
+			name: "should return a page, that sorts the given contexts by default (ascending)",
 			args: args{
-				APIConfig: &api.Config{
-					Contexts: map[string]*api.Context{
-						"kind-b": nil,
-						"kind-c": nil,
-						"kind-a": nil,
-					},
-				},
-				Config: &config.Config{
-					Group: config.Group{
-						Items: []config.GroupItem{
-							{
-								Name: "group-a",
-							},
-						},
-					},
-				},
-				State: &state.State{
-					Group: state.Group{
-						Active: "group-a",
-					},
-				},
+				APIConfig: &api.Config{Contexts: contexts},
+				State:     &state.State{},
 			},
-			want: &pterm.InteractiveSelectPrinter{
-				TextStyle: &pterm.Style{
-					pterm.FgLightCyan,
-				},
-				DefaultText: "Please select an option",
-				Options: []string{
-					"kind-a",
-					"kind-b",
-					"kind-c",
-				},
-				OptionStyle: &pterm.Style{
-					pterm.FgDefault,
-					pterm.BgDefault,
-				},
-				DefaultOption: "",
-				MaxHeight:     MaxSelectHeight,
-				Selector:      ">",
-				SelectorStyle: &pterm.Style{
-					pterm.FgLightMagenta,
+			want: []string{"kind-a", "kind-b", "kind-c"},
+		},
+		{
+			name: "should return a page, that sorts the given contexts descending and sets a default",
+			args: args{
+				Context: config.Context{
+					Selection: config.Selection{Sort: SortDesc, Default: "kind-b"},
 				},
+				APIConfig: &api.Config{Contexts: contexts},
+				State:     &state.State{},
 			},
-			wantErr: false,
+			want:        []string{"kind-c", "kind-b", "kind-a"},
+			wantDefault: "kind-b",
 		},
 		{
-			name: "should return a printer, that sorts the given contexts ascending",
+			name: "should return a page, that sets the default selection to the current context",
 			args: args{
-				APIConfig: &api.Config{
-					Contexts: map[string]*api.Context{
-						"kind-b": nil,
-						"kind-c": nil,
-						"kind-a": nil,
-					},
-				},
-				Config: &config.Config{
-					Group: config.Group{
-						Items: []config.GroupItem{
-							{
-								Name: "group-a",
-								Context: config.Context{
-									Selection: config.Selection{
-										Sort: SortAsc,
-									},
-								},
-							},
-						},
-					},
+				Context: config.Context{
+					Selection: config.Selection{Default: "-"},
 				},
+				APIConfig: &api.Config{Contexts: contexts, CurrentContext: "kind-b"},
 				State: &state.State{
-					Group: state.Group{
-						Active: "group-a",
-					},
-				},
-			},
-			want: &pterm.InteractiveSelectPrinter{
-				TextStyle: &pterm.Style{
-					pterm.FgLightCyan,
-				},
-				DefaultText: "Please select an option",
-				Options: []string{
-					"kind-a",
-					"kind-b",
-					"kind-c",
-				},
-				OptionStyle: &pterm.Style{
-					pterm.FgDefault,
-					pterm.BgDefault,
-				},
-				DefaultOption: "",
-				MaxHeight:     MaxSelectHeight,
-				Selector:      ">",
-				SelectorStyle: &pterm.Style{
-					pterm.FgLightMagenta,
+					Context: state.Context{Active: "kind-b"},
 				},
 			},
-			wantErr: false,
+			want:        []string{"kind-a", "kind-b", "kind-c"},
+			wantDefault: "kind-b",
 		},
 		{
-			name: "should return a printer, that sorts the given contexts ascending",
+			name: "should return a page, that floats the recently used contexts to the top",
 			args: args{
-				APIConfig: &api.Config{
-					Contexts: map[string]*api.Context{
-						"kind-b": nil,
-						"kind-c": nil,
-						"kind-a": nil,
-					},
-				},
-				Config: &config.Config{
-					Group: config.Group{
-						Items: []config.GroupItem{
-							{
-								Name: "group-a",
-								Context: config.Context{
-									Selection: config.Selection{
-										Sort: SortDesc,
-									},
-								},
-							},
-						},
-					},
-				},
+				APIConfig: &api.Config{Contexts: contexts},
 				State: &state.State{
-					Group: state.Group{
-						Active: "group-a",
+					Context: state.Context{
+						History: []state.History{"kind-b", "missing", "kind-c", "kind-b"},
 					},
 				},
 			},
-			want: &pterm.InteractiveSelectPrinter{
-				TextStyle: &pterm.Style{
-					pterm.FgLightCyan,
-				},
-				DefaultText: "Please select an option",
-				Options: []string{
-					"kind-c",
-					"kind-b",
-					"kind-a",
-				},
-				OptionStyle: &pterm.Style{
-					pterm.FgDefault,
-					pterm.BgDefault,
-				},
-				DefaultOption: "",
-				MaxHeight:     MaxSelectHeight,
-				Selector:      ">",
-				SelectorStyle: &pterm.Style{
-					pterm.FgLightMagenta,
-				},
-			},
-			wantErr: false,
+			want: []string{"kind-b", "kind-c", "kind-a"},
 		},
 		{
-			name: "should return a printer, that sorts the given contexts descending and set a default",
+			name: "should return a page, that sorts the contexts by recent use, instead of floating the history",
 			args: args{
-				APIConfig: &api.Config{
-					Contexts: map[string]*api.Context{
-						"kind-b": nil,
-						"kind-c": nil,
-						"kind-a": nil,
-					},
-				},
-				Config: &config.Config{
-					Group: config.Group{
-						Items: []config.GroupItem{
-							{
-								Name: "group-a",
-								Context: config.Context{
-									Default: "kind-b",
-									Selection: config.Selection{
-										Sort: SortDesc,
-									},
-								},
-							},
-						},
-					},
+				Context: config.Context{
+					Selection: config.Selection{Sort: SortRecent},
 				},
+				APIConfig: &api.Config{Contexts: contexts},
 				State: &state.State{
-					Group: state.Group{
-						Active: "group-a",
+					Context: state.Context{
+						History: []state.History{"kind-a"},
+						Usage: map[string]state.Usage{
+							"kind-a": {Count: 9, Last: time.Now().Add(-time.Hour)},
+							"kind-c": {Count: 1, Last: time.Now()},
+						},
 					},
 				},
 			},
-			want: &pterm.InteractiveSelectPrinter{
-				TextStyle: &pterm.Style{
-					pterm.FgLightCyan,
-				},
-				DefaultText: "Please select an option",
-				Options: []string{
-					"kind-c",
-					"kind-b",
-					"kind-a",
-				},
-				OptionStyle: &pterm.Style{
-					pterm.FgDefault,
-					pterm.BgDefault,
-				},
-				DefaultOption: "",
-				MaxHeight:     MaxSelectHeight,
-				Selector:      ">",
-				SelectorStyle: &pterm.Style{
-					pterm.FgLightMagenta,
-				},
-			},
-			wantErr: false,
+			want: []string{"kind-c", "kind-a", "kind-b"},
 		},
 		{
-			name: "should return a printer, that sets the default selection to the current context",
+			name: "should return a page, that sorts the contexts by frecency",
 			args: args{
-				APIConfig: &api.Config{
-					Contexts: map[string]*api.Context{
-						"kind-b": nil,
-						"kind-c": nil,
-						"kind-a": nil,
-					},
-					CurrentContext: "kind-b",
-				},
-				Config: &config.Config{
-					Group: config.Group{
-						Items: []config.GroupItem{
-							{
-								Name: "group-a",
-								Context: config.Context{
-									Selection: config.Selection{
-										Default: "-",
-									},
-								},
-							},
-						},
-					},
+				Context: config.Context{
+					Selection: config.Selection{Sort: SortFrecency},
 				},
+				APIConfig: &api.Config{Contexts: contexts},
 				State: &state.State{
-					Group: state.Group{
-						Active: "group-a",
-					},
 					Context: state.Context{
-						Active: "kind-b",
+						Usage: map[string]state.Usage{
+							"kind-a": {Count: 9, Last: time.Now().Add(-2 * time.Hour)},
+							"kind-c": {Count: 1, Last: time.Now()},
+						},
 					},
 				},
 			},
-			want: &pterm.InteractiveSelectPrinter{
-				TextStyle: &pterm.Style{
-					pterm.FgLightCyan,
-				},
-				DefaultText: "Please select an option",
-				Options: []string{
-					"kind-a",
-					"kind-b",
-					"kind-c",
-				},
-				OptionStyle: &pterm.Style{
-					pterm.FgDefault,
-					pterm.BgDefault,
-				},
-				DefaultOption: "kind-b",
-				MaxHeight:     MaxSelectHeight,
-				Selector:      ">",
-				SelectorStyle: &pterm.Style{
-					pterm.FgLightMagenta,
-				},
+			want: []string{"kind-a", "kind-c", "kind-b"},
+		},
+		{
+			name: "should return a page, that marks the protected contexts",
+			args: args{
+				Contexts:  []config.ContextItem{{Name: "*-c", Protected: true}},
+				APIConfig: &api.Config{Contexts: contexts},
+				State:     &state.State{},
 			},
-			wantErr: false,
+			want: []string{"kind-a", "kind-b", "kind-c" + ProtectedMarker},
+		},
+		{
+			name: "should return a page, that loads the contexts of another group",
+			args: args{
+				APIConfig: &api.Config{Contexts: contexts},
+				State:     &state.State{},
+				Group:     "group-b",
+			},
+			want: []string{"kind-dev", "kind-local", "kind-prod"},
 		},
-
 		{
 			name: "should return an error, as the default selection context does not exist",
 			args: args{
-				APIConfig: &api.Config{},
-				Config: &config.Config{
-					Group: config.Group{
-						Items: []config.GroupItem{
-							{
-								Name: "group-a",
-								Context: config.Context{
-									Selection: config.Selection{
-										Default: "kind-b",
-										Sort:    SortDesc,
-									},
-								},
-							},
-						},
-					},
-				},
-				State: &state.State{
-					Group: state.Group{
-						Active: "group-a",
-					},
+				Context: config.Context{
+					Selection: config.Selection{Default: "kind-d"},
 				},
+				APIConfig: &api.Config{Contexts: contexts},
+				State:     &state.State{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should return an error, as the group does not exist",
+			args: args{
+				APIConfig: &api.Config{Contexts: contexts},
+				State:     &state.State{},
+				Group:     "missing",
 			},
-			want:    nil,
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tt.args.State.Group.Active = "group-a"
+			groupName := tt.args.Group
+			if len(groupName) == 0 {
+				groupName = "group-a"
+			}
+
 			client := Client{
-				Config:    tt.args.Config,
+				Config: &config.Config{
+					Group: config.Group{
+						Items: []config.GroupItem{
+							{Name: "group-a", Context: tt.args.Context},
+							{Name: "group-b", Sources: []string{"dev"}},
+						},
+					},
+					Source: config.Source{
+						Items: []config.SourceItem{
+							{Name: "dev", Include: []string{kubeconfigFile}},
+						},
+					},
+					Context: config.Contexts{Items: tt.args.Contexts},
+				},
 				State:     tt.args.State,
 				APIConfig: tt.args.APIConfig,
 			}
 
-			got, err := client.buildInteractiveSelectPrinter()
+			got, err := client.buildPage(groupName)
 			if !tt.wantErr && err != nil {
 				t.Errorf("unexpected error, err: '%v'", err)
 			}
@@ -335,11 +280,50 @@ func Test_buildInteractiveSelectPrinter(t *testing.T) {
 				t.Errorf("expected error, got: '%v'", err)
 			}
 
-			options := cmpopts.IgnoreUnexported(pterm.InteractiveSelectPrinter{})
-			if !cmp.Equal(&tt.want, &got, options) {
-				diff := cmp.Diff(tt.want, got, options)
-				t.Errorf("group.Set() state mismatch (-want +got):\n%s", diff)
+			if got == nil {
+				return
+			}
+			labels := []string{}
+			for _, item := range got.Items {
+				labels = append(labels, item.Label)
+			}
+			if !cmp.Equal(tt.want, labels) {
+				diff := cmp.Diff(tt.want, labels)
+				t.Errorf("context.buildPage() mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantDefault != got.Default {
+				t.Errorf("want default: '%s', got: '%s'", tt.wantDefault, got.Default)
 			}
 		})
 	}
 }
+
+func Test_buildFinderItem(t *testing.T) {
+	apiConfig := &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"kind-dev": {Server: "https://127.0.0.1:6443"},
+		},
+		Contexts: map[string]*api.Context{
+			"kind-dev": {Cluster: "kind-dev", AuthInfo: "admin", Namespace: "kube-system"},
+		},
+	}
+
+	want := finder.Item{
+		Name:   "kind-dev",
+		Label:  "kind-dev" + ProtectedMarker,
+		Fields: []string{"kind-dev", "admin", "kube-system", "env=dev,team=a", "dev.yaml"},
+		Preview: "context:   kind-dev\n" +
+			"cluster:   kind-dev (https://127.0.0.1:6443)\n" +
+			"user:      admin\n" +
+			"namespace: kube-system\n" +
+			"protected: true\n" +
+			"files:     dev.yaml\n" +
+			"labels:    env=dev,team=a",
+	}
+
+	got := buildFinderItem(apiConfig, "kind-dev", true, []string{"dev.yaml"}, labels.Set{"team": "a", "env": "dev"})
+	if !cmp.Equal(want, got) {
+		diff := cmp.Diff(want, got)
+		t.Errorf("context.buildFinderItem() mismatch (-want +got):\n%s", diff)
+	}
+}
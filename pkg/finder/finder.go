@@ -0,0 +1,345 @@
+package finder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"atomicgo.dev/cursor"
+	"atomicgo.dev/keyboard"
+	"atomicgo.dev/keyboard/keys"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/pterm/pterm"
+)
+
+const (
+	DefaultMaxHeight = 15
+	Selector         = ">"
+)
+
+// Item is a single option of the finder
+type Item struct {
+	// Name is returned, if the item has been selected
+	Name string
+	// Label is displayed instead of the name, e.g. to add markers
+	Label string
+	// Fields are matched by the fuzzy search in addition to the name, e.g. the cluster or the source files
+	Fields []string
+	// Preview is displayed below the options, while the item is selected
+	Preview string
+}
+
+// Page is a set of items, e.g. all contexts of a single group
+type Page struct {
+	Name  string
+	Items []Item
+	// Default is the name of the item, that is selected initially
+	Default string
+}
+
+// Result contains the selected item and the page, that provides it
+type Result struct {
+	Page string
+	Item string
+}
+
+// Finder is an interactive fuzzy finder, that shows a preview of the selected item.
+// The left and right arrow keys switch between pages, each page is loaded on demand.
+type Finder struct {
+	MaxHeight int
+	// PageTitle describes the pages, e.g. group
+	PageTitle string
+	// Pages contains the names of all pages, that can be switched to
+	Pages []string
+	Load  func(page string) (*Page, error)
+
+	page     *Page
+	query    string
+	matches  []Item
+	selected int
+	offset   int
+	message  string
+	done     bool
+	canceled bool
+}
+
+// Show loads the given page and shows the finder, until an item has been selected or the finder has been canceled
+func (f *Finder) Show(page string) (*Result, error) {
+	err := f.open(page)
+	if err != nil {
+		return nil, err
+	}
+
+	// the terminal height limits the options, the header and the preview need some space as well
+	if height := pterm.GetTerminalHeight() - f.previewHeight() - 4; height > 0 && height < f.height() {
+		f.MaxHeight = height
+	}
+
+	area, err := pterm.DefaultArea.Start(f.render())
+	if err != nil {
+		return nil, fmt.Errorf("could not start area, err: '%w'", err)
+	}
+	cursor.Hide()
+	defer cursor.Show()
+
+	err = keyboard.Listen(func(key keys.Key) (bool, error) {
+		f.handle(key)
+		if f.done || f.canceled {
+			return true, nil
+		}
+		area.Update(f.render())
+		return false, nil
+	})
+	if err != nil {
+		_ = area.Stop()
+		return nil, fmt.Errorf("could not listen to the keyboard, err: '%w'", err)
+	}
+
+	area.Update(f.renderResult())
+	err = area.Stop()
+	if err != nil {
+		return nil, err
+	}
+	if f.canceled {
+		return nil, fmt.Errorf("the selection has been canceled")
+	}
+
+	return &Result{
+		Page: f.page.Name,
+		Item: f.matches[f.selected].Name,
+	}, nil
+}
+
+// open loads the given page and selects its default item
+func (f *Finder) open(name string) error {
+	page, err := f.Load(name)
+	if err != nil {
+		return err
+	}
+	f.page = page
+	f.query = ""
+	f.message = ""
+	f.filter()
+
+	for i, item := range f.matches {
+		if item.Name == page.Default {
+			f.selected = i
+		}
+	}
+	f.scroll()
+	return nil
+}
+
+// handle applies a single key press
+func (f *Finder) handle(key keys.Key) {
+	switch key.Code {
+	case keys.RuneKey:
+		f.query += key.String()
+		f.filter()
+	case keys.Space:
+		f.query += " "
+		f.filter()
+	case keys.Backspace:
+		if len(f.query) > 0 {
+			runes := []rune(f.query)
+			f.query = string(runes[:len(runes)-1])
+			f.filter()
+		}
+	case keys.CtrlU:
+		f.query = ""
+		f.filter()
+	case keys.Up, keys.CtrlP:
+		f.move(-1)
+	case keys.Down, keys.CtrlN:
+		f.move(1)
+	case keys.PgUp:
+		f.move(-f.height())
+	case keys.PgDown:
+		f.move(f.height())
+	case keys.Left, keys.ShiftTab:
+		f.switchPage(-1)
+	case keys.Right, keys.Tab:
+		f.switchPage(1)
+	case keys.Enter:
+		f.done = len(f.matches) > 0
+	case keys.CtrlC, keys.Escape:
+		f.canceled = true
+	}
+}
+
+// filter computes all items, that match every word of the query, items that match by name are ranked first
+func (f *Finder) filter() {
+	f.selected = 0
+	f.offset = 0
+
+	words := strings.Fields(f.query)
+	if len(words) == 0 {
+		f.matches = append([]Item{}, f.page.Items...)
+		return
+	}
+
+	type ranked struct {
+		item     Item
+		name     bool
+		distance int
+	}
+	var buffer []ranked
+	for _, item := range f.page.Items {
+		match := ranked{item: item, name: true}
+		for _, word := range words {
+			distance := fuzzy.RankMatchFold(word, item.Name)
+			if distance < 0 {
+				match.name = false
+				for _, field := range item.Fields {
+					rank := fuzzy.RankMatchFold(word, field)
+					if rank >= 0 && (distance < 0 || rank < distance) {
+						distance = rank
+					}
+				}
+			}
+			if distance < 0 {
+				match.distance = -1
+				break
+			}
+			match.distance += distance
+		}
+		if match.distance >= 0 {
+			buffer = append(buffer, match)
+		}
+	}
+
+	// the stable sort keeps the order of the page for equally ranked items
+	sort.SliceStable(buffer, func(i, j int) bool {
+		if buffer[i].name != buffer[j].name {
+			return buffer[i].name
+		}
+		return buffer[i].distance < buffer[j].distance
+	})
+
+	f.matches = make([]Item, 0, len(buffer))
+	for _, match := range buffer {
+		f.matches = append(f.matches, match.item)
+	}
+}
+
+// move moves the selection by the given offset, it wraps around at the first and the last item
+func (f *Finder) move(offset int) {
+	if len(f.matches) == 0 {
+		return
+	}
+	switch {
+	case f.selected+offset < 0 && f.selected > 0:
+		f.selected = 0
+	case f.selected+offset < 0:
+		f.selected = len(f.matches) - 1
+	case f.selected+offset >= len(f.matches) && f.selected < len(f.matches)-1:
+		f.selected = len(f.matches) - 1
+	case f.selected+offset >= len(f.matches):
+		f.selected = 0
+	default:
+		f.selected += offset
+	}
+	f.scroll()
+}
+
+// scroll keeps the selected item within the visible window
+func (f *Finder) scroll() {
+	height := f.height()
+	if f.selected < f.offset {
+		f.offset = f.selected
+	}
+	if f.selected >= f.offset+height {
+		f.offset = f.selected - height + 1
+	}
+}
+
+// switchPage loads the previous or next page, the current page is kept if it can not be loaded
+func (f *Finder) switchPage(offset int) {
+	if len(f.Pages) < 2 {
+		return
+	}
+	index := (f.pageIndex() + offset + len(f.Pages)) % len(f.Pages)
+
+	previous := f.page
+	err := f.open(f.Pages[index])
+	if err != nil {
+		f.page = previous
+		f.filter()
+		f.message = err.Error()
+	}
+}
+
+func (f *Finder) height() int {
+	if f.MaxHeight <= 0 {
+		return DefaultMaxHeight
+	}
+	return f.MaxHeight
+}
+
+func (f *Finder) previewHeight() int {
+	height := 0
+	for _, item := range f.page.Items {
+		if lines := strings.Count(item.Preview, "\n") + 1; lines > height {
+			height = lines
+		}
+	}
+	return height
+}
+
+func (f *Finder) render() string {
+	var builder strings.Builder
+
+	hint := "[type to search"
+	if len(f.Pages) > 1 {
+		hint += ", ←/→ to switch " + f.PageTitle
+	}
+	hint += "]"
+	builder.WriteString(pterm.Sprintf("%s %s: %s\n", pterm.ThemeDefault.PrimaryStyle.Sprint("Please select an option"), pterm.ThemeDefault.SecondaryStyle.Sprint(hint), f.query))
+
+	if len(f.Pages) > 1 {
+		builder.WriteString(pterm.Sprintf("%s: %s %s\n", f.PageTitle, pterm.ThemeDefault.SecondaryStyle.Sprint(f.page.Name), pterm.FgGray.Sprintf("(%d/%d)", f.pageIndex()+1, len(f.Pages))))
+	}
+	if len(f.message) > 0 {
+		builder.WriteString(pterm.Error.Sprint(f.message) + "\n")
+	}
+
+	end := f.offset + f.height()
+	if end > len(f.matches) {
+		end = len(f.matches)
+	}
+	for i := f.offset; i < end; i++ {
+		label := f.matches[i].Label
+		if len(label) == 0 {
+			label = f.matches[i].Name
+		}
+		if i == f.selected {
+			builder.WriteString(pterm.Sprintf("%s %s\n", pterm.ThemeDefault.SecondaryStyle.Sprint(Selector), label))
+		} else {
+			builder.WriteString(pterm.Sprintf("  %s\n", label))
+		}
+	}
+	builder.WriteString(pterm.FgGray.Sprintf("  %d/%d\n", len(f.matches), len(f.page.Items)))
+
+	if len(f.matches) > 0 && len(f.matches[f.selected].Preview) > 0 {
+		builder.WriteString(pterm.FgGray.Sprint(strings.Repeat("─", 40)) + "\n")
+		builder.WriteString(f.matches[f.selected].Preview + "\n")
+	}
+
+	return builder.String()
+}
+
+func (f *Finder) renderResult() string {
+	if f.canceled || len(f.matches) == 0 {
+		return ""
+	}
+	return pterm.Sprintf("%s: %s\n", pterm.ThemeDefault.PrimaryStyle.Sprint("Please select an option"), f.matches[f.selected].Name)
+}
+
+func (f *Finder) pageIndex() int {
+	for i, name := range f.Pages {
+		if name == f.page.Name {
+			return i
+		}
+	}
+	return 0
+}
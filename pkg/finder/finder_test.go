@@ -0,0 +1,211 @@
+package finder
+
+import (
+	"fmt"
+	"testing"
+
+	"atomicgo.dev/keyboard/keys"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_handle(t *testing.T) {
+	pages := map[string]*Page{
+		"dev": {
+			Name: "dev",
+			Items: []Item{
+				{Name: "kind-dev", Fields: []string{"cluster-dev", "default"}},
+				{Name: "kind-local", Fields: []string{"cluster-local", "kube-system"}},
+				{Name: "kind-prod", Fields: []string{"cluster-prod", "monitoring"}},
+			},
+			Default: "kind-local",
+		},
+		"prod": {
+			Name:  "prod",
+			Items: []Item{{Name: "eks-prod"}},
+		},
+	}
+	load := func(page string) (*Page, error) {
+		if match, ok := pages[page]; ok {
+			return match, nil
+		}
+		return nil, fmt.Errorf("could not find page '%s'", page)
+	}
+	runes := func(value string) []keys.Key {
+		var buffer []keys.Key
+		for _, r := range value {
+			buffer = append(buffer, keys.Key{Code: keys.RuneKey, Runes: []rune{r}})
+		}
+		return buffer
+	}
+
+	tests := []struct {
+		name         string
+		pages        []string
+		keys         []keys.Key
+		wantPage     string
+		wantMatches  []string
+		wantSelected string
+		wantDone     bool
+		wantCanceled bool
+		wantMessage  bool
+	}{
+		{
+			name:         "should select the default item",
+			keys:         []keys.Key{{Code: keys.Enter}},
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-dev", "kind-local", "kind-prod"},
+			wantSelected: "kind-local",
+			wantDone:     true,
+		},
+		{
+			name:         "should match the name fuzzy",
+			keys:         runes("kprd"),
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-prod"},
+			wantSelected: "kind-prod",
+		},
+		{
+			name:         "should match the fields, each word has to match",
+			keys:         runes("cluster monitoring"),
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-prod"},
+			wantSelected: "kind-prod",
+		},
+		{
+			name:         "should rank name matches first",
+			keys:         runes("dev"),
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-dev"},
+			wantSelected: "kind-dev",
+		},
+		{
+			name:         "should restore all items, after the query has been removed",
+			keys:         append(runes("xyz"), keys.Key{Code: keys.Backspace}, keys.Key{Code: keys.Backspace}, keys.Key{Code: keys.Backspace}),
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-dev", "kind-local", "kind-prod"},
+			wantSelected: "kind-dev",
+		},
+		{
+			name:         "should not finish, as no item matches",
+			keys:         append(runes("xyz"), keys.Key{Code: keys.Enter}),
+			wantPage:     "dev",
+			wantMatches:  []string{},
+			wantSelected: "",
+		},
+		{
+			name:         "should wrap around at the last item",
+			keys:         []keys.Key{{Code: keys.Down}, {Code: keys.Down}},
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-dev", "kind-local", "kind-prod"},
+			wantSelected: "kind-dev",
+		},
+		{
+			name:         "should wrap around at the first item",
+			keys:         []keys.Key{{Code: keys.Up}, {Code: keys.Up}},
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-dev", "kind-local", "kind-prod"},
+			wantSelected: "kind-prod",
+		},
+		{
+			name:         "should switch to the next page",
+			pages:        []string{"dev", "prod"},
+			keys:         []keys.Key{{Code: keys.Right}, {Code: keys.Enter}},
+			wantPage:     "prod",
+			wantMatches:  []string{"eks-prod"},
+			wantSelected: "eks-prod",
+			wantDone:     true,
+		},
+		{
+			name:         "should keep the page, as the previous page can not be loaded",
+			pages:        []string{"stage", "dev"},
+			keys:         []keys.Key{{Code: keys.Left}},
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-dev", "kind-local", "kind-prod"},
+			wantSelected: "kind-dev",
+			wantMessage:  true,
+		},
+		{
+			name:         "should cancel the selection",
+			keys:         []keys.Key{{Code: keys.CtrlC}},
+			wantPage:     "dev",
+			wantMatches:  []string{"kind-dev", "kind-local", "kind-prod"},
+			wantSelected: "kind-local",
+			wantCanceled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finder := Finder{
+				PageTitle: "group",
+				Pages:     tt.pages,
+				Load:      load,
+			}
+			err := finder.open("dev")
+			if err != nil {
+				t.Fatalf("unexpected error, err: '%v'", err)
+			}
+
+			for _, key := range tt.keys {
+				finder.handle(key)
+			}
+
+			matches := []string{}
+			for _, item := range finder.matches {
+				matches = append(matches, item.Name)
+			}
+			selected := ""
+			if len(finder.matches) > 0 {
+				selected = finder.matches[finder.selected].Name
+			}
+
+			if tt.wantPage != finder.page.Name {
+				t.Errorf("want page: '%s', got: '%s'", tt.wantPage, finder.page.Name)
+			}
+			if !cmp.Equal(tt.wantMatches, matches) {
+				diff := cmp.Diff(tt.wantMatches, matches)
+				t.Errorf("finder.handle() mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantSelected != selected {
+				t.Errorf("want selected: '%s', got: '%s'", tt.wantSelected, selected)
+			}
+			if tt.wantDone != finder.done || tt.wantCanceled != finder.canceled {
+				t.Errorf("want done: '%t' and canceled: '%t', got: '%t' and '%t'", tt.wantDone, tt.wantCanceled, finder.done, finder.canceled)
+			}
+			if tt.wantMessage != (len(finder.message) > 0) {
+				t.Errorf("want message: '%t', got: '%s'", tt.wantMessage, finder.message)
+			}
+		})
+	}
+}
+
+func Test_scroll(t *testing.T) {
+	items := make([]Item, 10)
+	for i := range items {
+		items[i] = Item{Name: fmt.Sprintf("kind-%d", i)}
+	}
+	finder := Finder{
+		MaxHeight: 3,
+		Load: func(page string) (*Page, error) {
+			return &Page{Name: page, Items: items, Default: "kind-5"}, nil
+		},
+	}
+	err := finder.open("dev")
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	// the default item is visible within the last row of the window
+	if finder.offset != 3 {
+		t.Errorf("want offset: '3', got: '%d'", finder.offset)
+	}
+
+	finder.handle(keys.Key{Code: keys.PgDown})
+	if finder.selected != 8 || finder.offset != 6 {
+		t.Errorf("want selected: '8' and offset: '6', got: '%d' and '%d'", finder.selected, finder.offset)
+	}
+
+	finder.handle(keys.Key{Code: keys.PgDown})
+	if finder.selected != 9 || finder.offset != 7 {
+		t.Errorf("want selected: '9' and offset: '7', got: '%d' and '%d'", finder.selected, finder.offset)
+	}
+}
@@ -0,0 +1,127 @@
+package finder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/logger"
+)
+
+const (
+	FzfBinary = "fzf"
+	// fzf exits with 1, if nothing matches and with 130, if the selection has been canceled
+	fzfExitNoMatch  = 1
+	fzfExitCanceled = 130
+)
+
+// Fzf selects an item with the external fzf binary
+type Fzf struct {
+	Binary string
+	// Prompt is displayed in front of the query, e.g. context
+	Prompt string
+	// Preview is executed by fzf for the selected item, {1} is replaced by the name of the item
+	Preview string
+}
+
+// NewFzf returns fzf, if it is the configured selector and installed, the builtin selector is used otherwise
+func NewFzf(currentConfig *config.Config, kind string) (*Fzf, bool) {
+	log := logger.New()
+
+	if currentConfig.Global.Selector != config.SelectorFzf {
+		return nil, false
+	}
+	binary, err := exec.LookPath(FzfBinary)
+	if err != nil {
+		log.Debug("could not find fzf, using the builtin selector", log.Args("error", err.Error()))
+		return nil, false
+	}
+
+	preview, err := PreviewCommand(kind)
+	if err != nil {
+		log.Debug("could not compute the preview command", log.Args("error", err.Error()))
+	}
+
+	return &Fzf{
+		Binary:  binary,
+		Prompt:  kind,
+		Preview: preview,
+	}, true
+}
+
+// PreviewCommand calls back into kontext, to print the given kind of the selected item as yaml
+func PreviewCommand(kind string) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("could not find the kontext executable, err: '%w'", err)
+	}
+
+	command := []string{quote(executable), "get", kind, "{1}", "-o", "yaml"}
+	if len(config.File) > 0 {
+		command = append(command, "--config", quote(config.File))
+	}
+	return strings.Join(command, " "), nil
+}
+
+// Show pipes all items of the given page to fzf and returns the selected item
+func (f *Fzf) Show(page *Page) (*Result, error) {
+	var input bytes.Buffer
+	for _, item := range page.Items {
+		label := item.Label
+		if len(label) == 0 {
+			label = item.Name
+		}
+		// the name is hidden, but kept as first field, to resolve the selected item
+		input.WriteString(strings.Join(append([]string{item.Name, label}, item.Fields...), "\t") + "\n")
+	}
+
+	args := []string{"--delimiter", "\t", "--with-nth", "2..", "--prompt", f.Prompt + "> "}
+	if len(page.Name) > 0 {
+		args = append(args, "--header", page.Name)
+	}
+	if len(f.Preview) > 0 {
+		args = append(args, "--preview", f.Preview)
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command(f.Binary, args...)
+	cmd.Stdin = &input
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+	// the preview must not wait for the lock, that is held by this process
+	cmd.Env = append(os.Environ(), config.PreviewEnvironmentVariable+"=true")
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == fzfExitCanceled:
+		return nil, fmt.Errorf("the selection has been canceled")
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == fzfExitNoMatch:
+		return nil, fmt.Errorf("no option matches the selection")
+	case err != nil:
+		return nil, fmt.Errorf("could not run fzf, err: '%w'", err)
+	}
+
+	name, _, _ := strings.Cut(strings.TrimRight(output.String(), "\r\n"), "\t")
+	if len(name) == 0 {
+		return nil, fmt.Errorf("fzf did not return a selection")
+	}
+
+	return &Result{
+		Page: page.Name,
+		Item: name,
+	}, nil
+}
+
+// quote escapes the given value for the shell, that fzf uses to run the preview
+func quote(value string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + value + `"`
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
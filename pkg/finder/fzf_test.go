@@ -0,0 +1,185 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+)
+
+// fakeFzf writes the arguments and the input of fzf to the given directory and selects the configured line
+const fakeFzf = `#!/bin/sh
+printf '%s\n' "$@" > "$FAKE_FZF_DIR/args"
+cat > "$FAKE_FZF_DIR/input"
+printf '%s' "$KONTEXT_PREVIEW" > "$FAKE_FZF_DIR/preview"
+if [ -n "$FAKE_FZF_EXIT" ]; then
+	exit "$FAKE_FZF_EXIT"
+fi
+grep "^$FAKE_FZF_SELECT	" "$FAKE_FZF_DIR/input"
+`
+
+func installFakeFzf(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake fzf requires a posix shell")
+	}
+
+	directory := t.TempDir()
+	err := os.WriteFile(filepath.Join(directory, FzfBinary), []byte(fakeFzf), 0755)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	t.Setenv("PATH", directory+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_FZF_DIR", directory)
+	return directory
+}
+
+func Test_NewFzf(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		install  bool
+		want     bool
+	}{
+		{
+			name:     "should return fzf, as it is configured and installed",
+			selector: config.SelectorFzf,
+			install:  true,
+			want:     true,
+		},
+		{
+			name:     "should fall back to the builtin selector, as fzf is not installed",
+			selector: config.SelectorFzf,
+		},
+		{
+			name:     "should use the builtin selector, as it is configured",
+			selector: config.SelectorBuiltin,
+			install:  true,
+		},
+		{
+			name:    "should use the builtin selector by default",
+			install: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.install {
+				installFakeFzf(t)
+			} else {
+				t.Setenv("PATH", t.TempDir())
+			}
+
+			got, ok := NewFzf(&config.Config{Global: config.Global{Selector: tt.selector}}, "context")
+			if tt.want != ok {
+				t.Errorf("want: '%t', got: '%t'", tt.want, ok)
+			}
+			if ok && !strings.Contains(got.Preview, "get context {1} -o yaml") {
+				t.Errorf("unexpected preview command: '%s'", got.Preview)
+			}
+		})
+	}
+}
+
+func Test_Fzf_Show(t *testing.T) {
+	page := &Page{
+		Name: "dev",
+		Items: []Item{
+			{Name: "kind-dev", Fields: []string{"cluster-dev", "default"}},
+			{Name: "kind-prod", Label: "kind-prod (protected)", Fields: []string{"cluster-prod"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		selection string
+		exit      string
+		want      *Result
+		wantInput string
+		wantErr   bool
+	}{
+		{
+			name:      "should return the name of the selected item",
+			selection: "kind-prod",
+			want:      &Result{Page: "dev", Item: "kind-prod"},
+			wantInput: "kind-dev\tkind-dev\tcluster-dev\tdefault\nkind-prod\tkind-prod (protected)\tcluster-prod\n",
+		},
+		{
+			name:      "should throw an error, as the selection has been canceled",
+			exit:      "130",
+			wantInput: "kind-dev\tkind-dev\tcluster-dev\tdefault\nkind-prod\tkind-prod (protected)\tcluster-prod\n",
+			wantErr:   true,
+		},
+		{
+			name:      "should throw an error, as nothing matches",
+			selection: "kind-missing",
+			wantInput: "kind-dev\tkind-dev\tcluster-dev\tdefault\nkind-prod\tkind-prod (protected)\tcluster-prod\n",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := installFakeFzf(t)
+			t.Setenv("FAKE_FZF_SELECT", tt.selection)
+			t.Setenv("FAKE_FZF_EXIT", tt.exit)
+
+			fzf := &Fzf{
+				Binary:  filepath.Join(directory, FzfBinary),
+				Prompt:  "context",
+				Preview: "kontext get context {1} -o yaml",
+			}
+			got, err := fzf.Show(page)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("fzf.Show() mismatch (-want +got):\n%s", diff)
+			}
+
+			input, err := os.ReadFile(filepath.Join(directory, "input"))
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if tt.wantInput != string(input) {
+				t.Errorf("want input: '%s', got: '%s'", tt.wantInput, input)
+			}
+
+			args, err := os.ReadFile(filepath.Join(directory, "args"))
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if !strings.Contains(string(args), "--preview\nkontext get context {1} -o yaml\n") {
+				t.Errorf("unexpected arguments: '%s'", args)
+			}
+
+			preview, err := os.ReadFile(filepath.Join(directory, "preview"))
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if string(preview) != "true" {
+				t.Errorf("want the preview environment variable, got: '%s'", preview)
+			}
+		})
+	}
+}
+
+func Test_quote(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows uses double quotes")
+	}
+
+	got := quote("/home/o'neil/kontext")
+	want := `'/home/o'\''neil/kontext'`
+	if want != got {
+		t.Errorf("want: '%s', got: '%s'", want, got)
+	}
+}
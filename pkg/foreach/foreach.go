@@ -0,0 +1,167 @@
+package foreach
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/signals"
+	"github.com/pterm/pterm"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const DefaultParallel = 4
+
+// Result is the outcome of the command for a single context
+type Result struct {
+	Context  string
+	ExitCode int
+	Duration time.Duration
+	// Err is set, if the command could not be started at all
+	Err error
+}
+
+// Runner runs a command once per context, each of them with its own minified kubeconfig
+type Runner struct {
+	APIConfig *api.Config
+	Command   []string
+	// Parallel limits the number of commands, that are running at the same time
+	Parallel int
+	Stdout   io.Writer
+	Stderr   io.Writer
+	// Signals forwards termination signals to the running commands, the remaining commands are skipped afterwards
+	Signals *signals.Forwarder
+}
+
+// Run spawns the command for all given contexts and returns their results in the same order
+func (r *Runner) Run(contexts []string) []Result {
+	results := make([]Result, len(contexts))
+	parallel := r.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	// all commands share the same writers, so complete lines must not interleave
+	mutex := &sync.Mutex{}
+	semaphore := make(chan struct{}, parallel)
+	waitGroup := sync.WaitGroup{}
+	for i, contextName := range contexts {
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, contextName string) {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+			results[i] = r.run(contextName, mutex)
+		}(i, contextName)
+	}
+	waitGroup.Wait()
+
+	return results
+}
+
+func (r *Runner) run(contextName string, mutex *sync.Mutex) Result {
+	log := logger.New().WithWriter(os.Stderr)
+	start := time.Now()
+	result := Result{Context: contextName, ExitCode: -1}
+
+	if len(r.Command) == 0 {
+		result.Err = fmt.Errorf("given command is empty")
+		return result
+	}
+	if sig := r.Signals.Received(); sig != nil {
+		result.Err = fmt.Errorf("skipped, received signal '%s'", sig)
+		return result
+	}
+
+	apiConfig, err := kubeconfig.Minify(r.APIConfig, contextName)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	file, err := os.CreateTemp("", "kontext-foreach-*.yaml")
+	if err != nil {
+		result.Err = fmt.Errorf("could not create temporary kubeconfig, err: '%w'", err)
+		return result
+	}
+	defer func() {
+		err := os.Remove(file.Name())
+		if err != nil {
+			log.Warn("could not remove temporary kubeconfig", log.Args("file", file.Name(), "err", err))
+		}
+	}()
+	err = kubeconfig.Write(file, apiConfig)
+	_ = file.Close()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	prefix := "[" + contextName + "] "
+	stdout := &prefixWriter{mutex: mutex, writer: r.Stdout, prefix: prefix}
+	stderr := &prefixWriter{mutex: mutex, writer: r.Stderr, prefix: prefix}
+
+	command := exec.Command(r.Command[0], r.Command[1:]...)
+	command.Stdout = stdout
+	command.Stderr = stderr
+	command.Env = append(os.Environ(), "KUBECONFIG="+file.Name())
+
+	err = command.Start()
+	if err == nil {
+		r.Signals.Add(command.Process)
+		err = command.Wait()
+		r.Signals.Remove(command.Process)
+	}
+	_ = stdout.Flush()
+	_ = stderr.Flush()
+	result.Duration = time.Since(start)
+
+	var exitError *exec.ExitError
+	switch {
+	case errors.As(err, &exitError):
+		result.ExitCode = signals.ExitCode(exitError.ProcessState)
+	case err != nil:
+		result.Err = err
+	default:
+		result.ExitCode = 0
+	}
+
+	return result
+}
+
+// Failed reports, whether at least one command did not succeed
+func Failed(results []Result) bool {
+	for _, result := range results {
+		if result.ExitCode != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildTablePrinter summarizes the exit code and duration of each command
+func BuildTablePrinter(results []Result) *pterm.TablePrinter {
+	table := pterm.TableData{
+		{"Context", "Exit Code", "Duration", "Error"},
+	}
+
+	for _, result := range results {
+		code := strconv.Itoa(result.ExitCode)
+		message := ""
+		if result.Err != nil {
+			code = "-"
+			message = result.Err.Error()
+		}
+		table = append(table, []string{
+			result.Context, code, result.Duration.Round(time.Millisecond).String(), message,
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithData(table)
+}
@@ -0,0 +1,136 @@
+package foreach
+
+import (
+	"bytes"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is a shell script")
+	}
+
+	apiConfig := &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"dev":  {Server: "https://127.0.0.1"},
+			"prod": {Server: "https://127.0.0.2"},
+		},
+		Contexts: map[string]*api.Context{
+			"dev":     {Cluster: "dev"},
+			"prod":    {Cluster: "prod"},
+			"missing": {Cluster: "missing"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		contexts   []string
+		command    []string
+		wantCodes  []int
+		wantErrs   []bool
+		wantStdout []string
+		wantStderr []string
+		wantFailed bool
+	}{
+		{
+			name:     "should run the command once per context with its own kubeconfig",
+			contexts: []string{"dev", "prod"},
+			// the kubeconfig only contains a single context
+			command:    []string{"sh", "-c", `grep -c "name: " "$KUBECONFIG"; grep current-context "$KUBECONFIG"`},
+			wantCodes:  []int{0, 0},
+			wantErrs:   []bool{false, false},
+			wantStdout: []string{"[dev] 2", "[dev] current-context: dev", "[prod] 2", "[prod] current-context: prod"},
+			wantStderr: []string{},
+		},
+		{
+			name:       "should propagate the exit code and prefix stderr, even without a trailing newline",
+			contexts:   []string{"dev", "prod"},
+			command:    []string{"sh", "-c", `printf failed >&2; exit 3`},
+			wantCodes:  []int{3, 3},
+			wantErrs:   []bool{false, false},
+			wantStdout: []string{},
+			wantStderr: []string{"[dev] failed", "[prod] failed"},
+			wantFailed: true,
+		},
+		{
+			name:       "should report an error, as the cluster of the context does not exist",
+			contexts:   []string{"missing"},
+			command:    []string{"true"},
+			wantCodes:  []int{-1},
+			wantErrs:   []bool{true},
+			wantStdout: []string{},
+			wantStderr: []string{},
+			wantFailed: true,
+		},
+		{
+			name:       "should report an error, as the command does not exist",
+			contexts:   []string{"dev"},
+			command:    []string{"kontext-command-that-does-not-exist"},
+			wantCodes:  []int{-1},
+			wantErrs:   []bool{true},
+			wantStdout: []string{},
+			wantStderr: []string{},
+			wantFailed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			runner := Runner{
+				APIConfig: apiConfig,
+				Command:   tt.command,
+				Parallel:  2,
+				Stdout:    stdout,
+				Stderr:    stderr,
+			}
+
+			results := runner.Run(tt.contexts)
+
+			for i, result := range results {
+				if result.Context != tt.contexts[i] {
+					t.Errorf("want context: '%s', got: '%s'", tt.contexts[i], result.Context)
+				}
+				if result.ExitCode != tt.wantCodes[i] {
+					t.Errorf("want exit code: '%d', got: '%d'", tt.wantCodes[i], result.ExitCode)
+				}
+				if (result.Err != nil) != tt.wantErrs[i] {
+					t.Errorf("want error: '%t', got: '%v'", tt.wantErrs[i], result.Err)
+				}
+			}
+
+			if Failed(results) != tt.wantFailed {
+				t.Errorf("want failed: '%t', got: '%t'", tt.wantFailed, Failed(results))
+			}
+
+			// the commands run in parallel, so only the lines are compared, not their order
+			for _, output := range []struct {
+				want []string
+				got  *bytes.Buffer
+			}{{tt.wantStdout, stdout}, {tt.wantStderr, stderr}} {
+				got := lines(output.got.String())
+				if !cmp.Equal(output.want, got) {
+					diff := cmp.Diff(output.want, got)
+					t.Errorf("foreach.Run() output mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func lines(output string) []string {
+	buffer := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) > 0 {
+			buffer = append(buffer, line)
+		}
+	}
+	sort.Strings(buffer)
+	return buffer
+}
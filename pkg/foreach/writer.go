@@ -0,0 +1,49 @@
+package foreach
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// prefixWriter prefixes each line with the given prefix and only writes complete lines to the shared writer
+type prefixWriter struct {
+	mutex  *sync.Mutex
+	writer io.Writer
+	prefix string
+	buffer []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+	for {
+		index := bytes.IndexByte(w.buffer, '\n')
+		if index < 0 {
+			break
+		}
+		err := w.writeLine(w.buffer[:index+1])
+		if err != nil {
+			return 0, err
+		}
+		w.buffer = w.buffer[index+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes the remaining output, that has not been terminated by a newline
+func (w *prefixWriter) Flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	line := append(w.buffer, '\n')
+	w.buffer = nil
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err := w.writer.Write(append([]byte(w.prefix), line...))
+	return err
+}
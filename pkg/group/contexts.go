@@ -0,0 +1,64 @@
+package group
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/source"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Contexts merges the sources of the given group, or of the active group if the name is empty, and returns the
+// merged api config together with all context names, whose name matches the glob pattern and whose labels match the
+// label selector. An empty pattern or selector matches every context.
+func (c *Client) Contexts(groupName string, pattern string, selector string) (*api.Config, []string, error) {
+	if len(groupName) == 0 {
+		groupName = c.State.Group.Active
+	}
+	if len(groupName) == 0 {
+		return nil, nil, fmt.Errorf("no group given and no active group")
+	}
+
+	group, err := c.Get(groupName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid selector '%s', err: '%w'", selector, err)
+	}
+
+	kubeconfigs, err := source.LoadGroup(c.Config, group, source.ExecRun)
+	if err != nil {
+		return nil, nil, err
+	}
+	apiConfig, _ := source.Merge(kubeconfigs)
+
+	contextLabels, err := source.ComputeLabels(c.Config, kubeconfigs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buffer := []string{}
+	for name := range apiConfig.Contexts {
+		if len(pattern) > 0 {
+			match, err := doublestar.Match(pattern, name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid pattern '%s', err: '%w'", pattern, err)
+			}
+			if !match {
+				continue
+			}
+		}
+		if !labelSelector.Matches(contextLabels[name]) {
+			continue
+		}
+		buffer = append(buffer, name)
+	}
+	sort.Strings(buffer)
+
+	return apiConfig, buffer, nil
+}
@@ -0,0 +1,129 @@
+package group
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+)
+
+func Test_Contexts(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "02-valid-kubeconfig-multiple-contexts.yaml")
+
+	currentConfig := &config.Config{
+		Group: config.Group{
+			Items: []config.GroupItem{
+				{Name: "dev", Sources: []string{"dev"}},
+				{Name: "empty"},
+			},
+		},
+		Source: config.Source{
+			Items: []config.SourceItem{{Name: "dev", Include: []string{kubeconfigFile}, Labels: map[string]string{"env": "dev"}}},
+		},
+		Label: config.Label{
+			Items: []config.LabelItem{{File: kubeconfigFile, Labels: map[string]string{"team": "a"}}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		state     *state.State
+		groupName string
+		pattern   string
+		selector  string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "should return all contexts of the given group sorted by name",
+			state:     &state.State{},
+			groupName: "dev",
+			want:      []string{"kind-dev", "kind-local", "kind-prod"},
+		},
+		{
+			name:    "should return all contexts of the active group, that match the pattern",
+			state:   &state.State{Group: state.Group{Active: "dev"}},
+			pattern: "*-prod",
+			want:    []string{"kind-prod"},
+		},
+		{
+			name:      "should return all contexts, that match the label selector",
+			state:     &state.State{},
+			groupName: "dev",
+			selector:  "env=dev,team in (a,b)",
+			want:      []string{"kind-dev", "kind-local", "kind-prod"},
+		},
+		{
+			name:      "should return all contexts, that match the pattern and the label selector",
+			state:     &state.State{},
+			groupName: "dev",
+			pattern:   "kind-d*",
+			selector:  "team=a",
+			want:      []string{"kind-dev"},
+		},
+		{
+			name:      "should return an empty list, as no context matches the label selector",
+			state:     &state.State{},
+			groupName: "dev",
+			selector:  "env=prod",
+			want:      []string{},
+		},
+		{
+			name:      "should return an empty list, as the group has no sources",
+			state:     &state.State{},
+			groupName: "empty",
+			want:      []string{},
+		},
+		{
+			name:    "should throw an error, as there is no active group",
+			state:   &state.State{},
+			wantErr: true,
+		},
+		{
+			name:      "should throw an error, as the group does not exist",
+			state:     &state.State{},
+			groupName: "prod",
+			wantErr:   true,
+		},
+		{
+			name:      "should throw an error, as the pattern is invalid",
+			state:     &state.State{},
+			groupName: "dev",
+			pattern:   "kind-[",
+			wantErr:   true,
+		},
+		{
+			name:      "should throw an error, as the label selector is invalid",
+			state:     &state.State{},
+			groupName: "dev",
+			selector:  "env in (",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: currentConfig,
+				State:  tt.state,
+			}
+
+			_, got, err := client.Contexts(tt.groupName, tt.pattern, tt.selector)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("group.Contexts() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package group
+
+import (
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/source"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Export merges the sources of the given group and only keeps the clusters and users, that are referred by a context.
+// The default context of the group becomes the current context, if it is configured.
+func (c *Client) Export(groupName string) (*api.Config, error) {
+	group, err := c.Get(groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	apiConfig, _, err := source.MergeGroup(c.Config, group, source.ExecRun)
+	if err != nil {
+		return nil, err
+	}
+	if len(group.Context.Default) > 0 {
+		apiConfig.CurrentContext = group.Context.Default
+	}
+
+	return kubeconfig.Prune(apiConfig), nil
+}
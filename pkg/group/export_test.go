@@ -0,0 +1,86 @@
+package group
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
+)
+
+func Test_Export(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "02-valid-kubeconfig-multiple-contexts.yaml")
+
+	currentConfig := &config.Config{
+		Group: config.Group{
+			Items: []config.GroupItem{
+				{Name: "dev", Sources: []string{"dev"}},
+				{Name: "prod", Sources: []string{"dev"}, Context: config.Context{Default: "kind-prod"}},
+			},
+		},
+		Source: config.Source{
+			Items: []config.SourceItem{{Name: "dev", Include: []string{kubeconfigFile}}},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		groupName          string
+		wantCurrentContext string
+		wantContexts       []string
+		wantErr            bool
+	}{
+		{
+			name:               "should export all contexts of the group",
+			groupName:          "dev",
+			wantCurrentContext: "kind-dev",
+			wantContexts:       []string{"kind-dev", "kind-local", "kind-prod"},
+		},
+		{
+			name:               "should export all contexts of the group and use the default context",
+			groupName:          "prod",
+			wantCurrentContext: "kind-prod",
+			wantContexts:       []string{"kind-dev", "kind-local", "kind-prod"},
+		},
+		{
+			name:      "should throw an error, as the group does not exist",
+			groupName: "stage",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: currentConfig,
+				State:  &state.State{},
+			}
+
+			got, err := client.Export(tt.groupName)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			if tt.wantCurrentContext != got.CurrentContext {
+				t.Errorf("want current context: '%s', got: '%s'", tt.wantCurrentContext, got.CurrentContext)
+			}
+			contexts := lo.Keys(got.Contexts)
+			sort.Strings(contexts)
+			if !cmp.Equal(tt.wantContexts, contexts) {
+				diff := cmp.Diff(tt.wantContexts, contexts)
+				t.Errorf("group.Export() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
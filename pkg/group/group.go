@@ -3,6 +3,7 @@ package group
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/orbatschow/kontext/pkg/config"
 	"github.com/orbatschow/kontext/pkg/context"
@@ -19,17 +20,21 @@ const (
 	PreviousGroupAlias = "-"
 	SortAsc            = "asc"
 	SortDesc           = "desc"
+	SortRecent         = "recent"
+	SortFrecency       = "frecency"
 )
 
 type Client struct {
 	Config    *config.Config
 	State     *state.State
 	APIConfig *api.Config
+	// Yes skips the confirmation of protected contexts
+	Yes bool
 }
 
-func New() (*Client, error) {
+func New(configFile string) (*Client, error) {
 	configClient := &config.Client{
-		File: config.DefaultConfigPath,
+		File: configFile,
 	}
 	config, err := configClient.Read()
 	if err != nil {
@@ -77,19 +82,13 @@ func (c *Client) Set(groupName string) error {
 	}
 
 	if len(groupName) == 0 {
-		printer, err := c.buildInteractiveSelectPrinter()
-		if err != nil {
-			return err
-		}
-
-		groupName, err = printer.Show()
+		var err error
+		groupName, err = c.selectGroup()
 		if err != nil {
 			return err
 		}
 	}
 
-	var files []*os.File
-
 	group, ok := lo.Find(c.Config.Group.Items, func(item config.GroupItem) bool {
 		return item.Name == groupName
 	})
@@ -97,57 +96,113 @@ func (c *Client) Set(groupName string) error {
 		return fmt.Errorf("could not find group: '%s", groupName)
 	}
 
-	for _, sourceName := range group.Sources {
-		sourceMatch, ok := lo.Find(c.Config.Source.Items, func(item config.SourceItem) bool {
-			return sourceName == item.Name
-		})
-		if !ok {
-			log.Warn("could not find source", log.Args("source", sourceName, "group", groupName))
-			continue
-		}
-		match, err := source.ComputeFiles(&sourceMatch)
-		if err != nil {
-			return err
-		}
-		files = append(files, match...)
-	}
-
-	apiConfig, err := kubeconfig.Merge(files...)
+	apiConfig, conflicts, err := source.MergeGroup(c.Config, &group, source.ExecRun)
 	if err != nil {
 		return err
 	}
+	for _, conflict := range conflicts {
+		log.Warn(conflict.String(), log.Args("group", groupName, "hint", "configure source.items[].rename"))
+	}
+	c.restoreNamespaces(apiConfig)
 
-	// if the group has a default context, set it
-	defaultContext := group.Context.Default
-	if len(defaultContext) > 0 {
+	// the default context is protected by the new group
+	previousGroup := c.State.Group.Active
+	c.State.Group.Active = groupName
+
+	// restore the last active context of the group, the default context is set otherwise
+	contextName := group.Context.Default
+	if previousContext, ok := c.State.Group.Contexts[groupName]; ok {
+		if _, ok := apiConfig.Contexts[previousContext]; ok {
+			contextName = previousContext
+		} else {
+			log.Warn("the previous context of the group is no longer provided", log.Args("group", groupName, "context", previousContext))
+		}
+	}
+	if len(contextName) > 0 {
 		contextClient := context.Client{
 			Config:    c.Config,
 			State:     c.State,
 			APIConfig: apiConfig,
+			Yes:       c.Yes,
 		}
-		err := contextClient.Set(defaultContext)
+		err := contextClient.Set(contextName)
 		if err != nil {
+			c.State.Group.Active = previousGroup
 			return err
 		}
 	} else {
+		// the merge decides about the context, the state has to follow it
+		now := time.Now()
+		c.State.Context.Active = apiConfig.CurrentContext
+		c.State.Context.Activated = lo.Ternary(len(apiConfig.CurrentContext) > 0, &now, nil)
+		c.State.Group.Contexts = state.ComputeContexts(groupName, apiConfig.CurrentContext, c.State.Group.Contexts)
 		log.Info("switched context", log.Args("context", apiConfig.CurrentContext))
 	}
 
 	// set new api config and modify state
 	c.APIConfig = apiConfig
-	c.State.Group.Active = groupName
 	c.State.Group.History = state.ComputeHistory(c.Config, state.History(groupName), c.State.Group.History)
+	c.State.Group.Usage = state.ComputeUsage(groupName, c.State.Group.Usage, time.Now())
 
 	log.Info("switched group", log.Args("group", groupName))
 	return nil
 }
 
+// Reload merges the sources of the active group again, the active context and its namespace are kept, if the group
+// still provides the context. Otherwise, the group is set again.
 func (c *Client) Reload() error {
+	log := logger.New()
 	groupName := c.State.Group.Active
+	contextName := c.State.Context.Active
 
-	err := c.Set(groupName)
+	group, err := c.Get(groupName)
 	if err != nil {
 		return err
 	}
+	apiConfig, conflicts, err := source.MergeGroup(c.Config, group, source.ExecRun)
+	if err != nil {
+		return err
+	}
+	c.restoreNamespaces(apiConfig)
+
+	if context, ok := apiConfig.Contexts[contextName]; ok {
+		for _, conflict := range conflicts {
+			log.Warn(conflict.String(), log.Args("group", groupName, "hint", "configure source.items[].rename"))
+		}
+		// the namespace has been set by the user, the sources do not know about it
+		if previous, ok := c.APIConfig.Contexts[contextName]; ok && len(previous.Namespace) > 0 {
+			context.Namespace = previous.Namespace
+		}
+		apiConfig.CurrentContext = contextName
+		c.APIConfig = apiConfig
+
+		log.Info("reloaded group", log.Args("group", groupName, "context", contextName))
+		return nil
+	}
+
+	if len(contextName) > 0 {
+		log.Warn("the active context is no longer provided by the group", log.Args("group", groupName, "context", contextName))
+	}
+	// the missing context must not be restored, a reload is not counted as a use of the group or its context
+	groupUsage, contextUsage := c.State.Group.Usage, c.State.Context.Usage
+	if _, ok := c.State.Group.Contexts[groupName]; ok {
+		c.State.Group.Contexts = lo.OmitByKeys(c.State.Group.Contexts, []string{groupName})
+	}
+
+	err = c.Set(groupName)
+	if err != nil {
+		return err
+	}
+	c.State.Group.Usage, c.State.Context.Usage = groupUsage, contextUsage
 	return nil
 }
+
+// restoreNamespaces applies the namespaces, that have been set per context, to the merged api config
+// The sources do not know about them, so they would be lost whenever the group is merged again.
+func (c *Client) restoreNamespaces(apiConfig *api.Config) {
+	for contextName, namespaceName := range c.State.Namespace.Active {
+		if context, ok := apiConfig.Contexts[contextName]; ok {
+			context.Namespace = namespaceName
+		}
+	}
+}
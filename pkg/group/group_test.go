@@ -8,10 +8,17 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/namespace"
 	"github.com/orbatschow/kontext/pkg/state"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+// activated ignores the activation timestamp of the context, as it is not deterministic
+var activated = cmp.Options{
+	cmpopts.IgnoreFields(state.Context{}, "Activated"),
+	cmpopts.IgnoreFields(state.Usage{}, "Last"),
+}
+
 func Test_Get(t *testing.T) {
 	type args struct {
 		GroupName string
@@ -150,6 +157,7 @@ func Test_Set(t *testing.T) {
 						History: []state.History{
 							"dev",
 						},
+						Usage: map[string]state.Usage{"dev": {Count: 1}},
 					},
 					Context: state.Context{},
 				},
@@ -227,12 +235,15 @@ func Test_Set(t *testing.T) {
 						History: []state.History{
 							"dev",
 						},
+						Usage:    map[string]state.Usage{"dev": {Count: 1}},
+						Contexts: map[string]string{"dev": "kind-dev"},
 					},
 					Context: state.Context{
 						Active: "kind-dev",
 						History: []state.History{
 							"kind-dev",
 						},
+						Usage: map[string]state.Usage{"kind-dev": {Count: 1}},
 					},
 				},
 			},
@@ -302,8 +313,8 @@ func Test_Set(t *testing.T) {
 				t.Errorf("expected error, got: '%v'", err)
 			}
 
-			if !tt.wantErr && !cmp.Equal(tt.want.State, client.State) {
-				diff := cmp.Diff(&tt.want.State, &client.State)
+			if !tt.wantErr && !cmp.Equal(tt.want.State, client.State, activated) {
+				diff := cmp.Diff(&tt.want.State, &client.State, activated)
 				t.Errorf("group.Set() state mismatch (-want +got):\n%s", diff)
 			}
 
@@ -400,10 +411,188 @@ func Test_Reload(t *testing.T) {
 				t.Errorf("expected error, got: '%v'", err)
 			}
 
-			if !tt.wantErr && !cmp.Equal(tt.want, client.State) {
-				diff := cmp.Diff(&tt.want, &client.State)
+			if !tt.wantErr && !cmp.Equal(tt.want, client.State, activated) {
+				diff := cmp.Diff(&tt.want, &client.State, activated)
 				t.Errorf("group.Set() state mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+// contextConfig returns a config with the groups dev and prod, that refer to a kubeconfig with multiple contexts
+func contextConfig() *config.Config {
+	_, caller, _, _ := runtime.Caller(0)
+
+	return &config.Config{
+		State: config.State{
+			History: config.History{
+				Size: state.DefaultMaximumHistorySize,
+			},
+		},
+		Group: config.Group{
+			Items: []config.GroupItem{
+				{Name: "dev", Sources: []string{"dev"}, Context: config.Context{Default: "kind-dev"}},
+				{Name: "prod", Sources: []string{"dev"}, Context: config.Context{Default: "kind-prod"}},
+			},
+		},
+		Source: config.Source{
+			Items: []config.SourceItem{
+				{Name: "dev", Include: []string{filepath.Join(caller, "..", "testdata", "02-valid-kubeconfig-multiple-contexts.yaml")}},
+			},
+		},
+	}
+}
+
+func Test_Set_PreviousContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		contexts     map[string]string
+		want         string
+		wantContexts map[string]string
+	}{
+		{
+			name:         "should restore the last active context of the group instead of its default context",
+			contexts:     map[string]string{"dev": "kind-local", "prod": "kind-dev"},
+			want:         "kind-local",
+			wantContexts: map[string]string{"dev": "kind-local", "prod": "kind-dev"},
+		},
+		{
+			name:         "should set the default context, as the group does not provide its last active context",
+			contexts:     map[string]string{"dev": "kind-removed"},
+			want:         "kind-dev",
+			wantContexts: map[string]string{"dev": "kind-dev"},
+		},
+		{
+			name:         "should set the default context, as the group has no last active context",
+			want:         "kind-dev",
+			wantContexts: map[string]string{"dev": "kind-dev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: contextConfig(),
+				State: &state.State{
+					Group: state.Group{Active: "prod", Contexts: tt.contexts},
+				},
+			}
+
+			err := client.Set("dev")
+			if err != nil {
+				t.Fatalf("unexpected error, err: '%v'", err)
+			}
+
+			if client.APIConfig.CurrentContext != tt.want || client.State.Context.Active != tt.want {
+				t.Errorf("want context '%s', got: '%s' and '%s'", tt.want, client.APIConfig.CurrentContext, client.State.Context.Active)
+			}
+			if !cmp.Equal(tt.wantContexts, client.State.Group.Contexts) {
+				diff := cmp.Diff(tt.wantContexts, client.State.Group.Contexts)
+				t.Errorf("group.Set() contexts mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Reload_ActiveContext(t *testing.T) {
+	tests := []struct {
+		name          string
+		context       string
+		want          string
+		wantNamespace string
+		wantHistory   []state.History
+	}{
+		{
+			name:          "should keep the active context and its namespace, as the group still provides it",
+			context:       "kind-local",
+			want:          "kind-local",
+			wantNamespace: "monitoring",
+			wantHistory:   []state.History{"kind-local"},
+		},
+		{
+			name:        "should set the default context, as the group no longer provides the active context",
+			context:     "kind-removed",
+			want:        "kind-dev",
+			wantHistory: []state.History{"kind-removed", "kind-dev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: contextConfig(),
+				State: &state.State{
+					Group: state.Group{
+						Active:   "dev",
+						Contexts: map[string]string{"dev": tt.context},
+					},
+					Context: state.Context{
+						Active:  tt.context,
+						History: []state.History{state.History(tt.context)},
+					},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: tt.context,
+					Contexts: map[string]*api.Context{
+						tt.context: {Cluster: tt.context, Namespace: "monitoring"},
+					},
+				},
+			}
+
+			err := client.Reload()
+			if err != nil {
+				t.Fatalf("unexpected error, err: '%v'", err)
+			}
+
+			if client.APIConfig.CurrentContext != tt.want || client.State.Context.Active != tt.want {
+				t.Errorf("want context '%s', got: '%s' and '%s'", tt.want, client.APIConfig.CurrentContext, client.State.Context.Active)
+			}
+			if namespace := client.APIConfig.Contexts[tt.want].Namespace; namespace != tt.wantNamespace {
+				t.Errorf("want namespace '%s', got: '%s'", tt.wantNamespace, namespace)
+			}
+			if !cmp.Equal(tt.wantHistory, client.State.Context.History) {
+				diff := cmp.Diff(tt.wantHistory, client.State.Context.History)
+				t.Errorf("group.Reload() history mismatch (-want +got):\n%s", diff)
+			}
+			if client.State.Group.Contexts["dev"] != tt.want {
+				t.Errorf("want last active context '%s', got: '%s'", tt.want, client.State.Group.Contexts["dev"])
+			}
+		})
+	}
+}
+
+func Test_Set_Namespace(t *testing.T) {
+	client := Client{
+		Config: contextConfig(),
+		State: &state.State{
+			Group: state.Group{Active: "prod"},
+		},
+	}
+
+	err := client.Set("dev")
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	namespaceClient := namespace.Client{
+		Config:    client.Config,
+		State:     client.State,
+		APIConfig: client.APIConfig,
+	}
+	err = namespaceClient.Set("monitoring")
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	// the namespace has to survive the round trip through another group and a reload
+	for _, step := range []func() error{
+		func() error { return client.Set("prod") },
+		func() error { return client.Set("dev") },
+		client.Reload,
+	} {
+		err := step()
+		if err != nil {
+			t.Fatalf("unexpected error, err: '%v'", err)
+		}
+		if got := client.APIConfig.Contexts["kind-dev"].Namespace; got != "monitoring" {
+			t.Errorf("want namespace 'monitoring', got: '%s'", got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package group
+
+import (
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/samber/lo"
+)
+
+// Item is the machine-readable representation of a group, its fields are part of the public output schema
+type Item struct {
+	Name    string   `json:"name"`
+	Active  bool     `json:"active"`
+	Sources []string `json:"sources"`
+	// Groups contains the referenced groups, whose contexts are part of the group
+	Groups []string `json:"groups"`
+	// Selector selects the contexts of the group by their labels
+	Selector string `json:"selector"`
+	// Files contains the computed files of all sources, that are referred by the group
+	Files []string `json:"files"`
+}
+
+// BuildItems converts the given groups into items, while keeping their order
+func (c *Client) BuildItems(groups ...config.GroupItem) ([]Item, error) {
+	buffer := []Item{}
+
+	for _, group := range groups {
+		group := group
+		files, err := source.ComputeGroupFiles(c.Config, &group, source.ExecCached)
+		if err != nil {
+			return nil, err
+		}
+
+		names := []string{}
+		for _, file := range files {
+			names = append(names, file.Name())
+			_ = file.Close()
+		}
+
+		buffer = append(buffer, Item{
+			Name:     group.Name,
+			Active:   group.Name == c.State.Group.Active,
+			Sources:  lo.Ternary(group.Sources == nil, []string{}, group.Sources),
+			Groups:   lo.Ternary(group.Groups == nil, []string{}, group.Groups),
+			Selector: group.Selector,
+			Files:    names,
+		})
+	}
+
+	return buffer, nil
+}
@@ -0,0 +1,59 @@
+package group
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+)
+
+func Test_BuildItems(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+
+	groups := []config.GroupItem{
+		{Name: "dev", Sources: []string{"dev"}},
+		{Name: "prod", Sources: []string{"missing"}},
+		{Name: "empty"},
+		{Name: "nested", Groups: []string{"dev", "prod"}},
+		{Name: "excluded", Groups: []string{"dev"}, ExcludeSources: []string{"dev"}},
+	}
+
+	client := Client{
+		Config: &config.Config{
+			Group: config.Group{
+				Items: groups,
+			},
+			Source: config.Source{
+				Items: []config.SourceItem{
+					{Name: "dev", Include: []string{kubeconfigFile}},
+				},
+			},
+		},
+		State: &state.State{
+			Group: state.Group{
+				Active: "dev",
+			},
+		},
+	}
+
+	want := []Item{
+		{Name: "dev", Active: true, Sources: []string{"dev"}, Groups: []string{}, Files: []string{kubeconfigFile}},
+		{Name: "prod", Sources: []string{"missing"}, Groups: []string{}, Files: []string{}},
+		{Name: "empty", Sources: []string{}, Groups: []string{}, Files: []string{}},
+		{Name: "nested", Sources: []string{}, Groups: []string{"dev", "prod"}, Files: []string{kubeconfigFile}},
+		{Name: "excluded", Sources: []string{}, Groups: []string{"dev"}, Files: []string{}},
+	}
+
+	got, err := client.BuildItems(groups...)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if !cmp.Equal(want, got) {
+		diff := cmp.Diff(want, got)
+		t.Errorf("group.BuildItems() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -24,3 +24,21 @@ func (c *Client) BuildTablePrinter(groups ...config.GroupItem) *pterm.TablePrint
 
 	return pterm.DefaultTable.WithHasHeader().WithData(table)
 }
+
+func (c *Client) BuildWideTablePrinter(items ...Item) *pterm.TablePrinter {
+	table := pterm.TableData{
+		{"Active", "Name", "Source(s)", "Group(s)", "Selector", "File(s)"},
+	}
+
+	for _, item := range items {
+		active := ""
+		if item.Active {
+			active = "*"
+		}
+		table = append(table, []string{
+			active, item.Name, strings.Join(item.Sources, "\n"), strings.Join(item.Groups, "\n"), item.Selector, strings.Join(item.Files, "\n"),
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithData(table)
+}
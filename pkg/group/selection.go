@@ -3,12 +3,38 @@ package group
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/finder"
+	"github.com/orbatschow/kontext/pkg/state"
 	"github.com/pterm/pterm"
 	"github.com/samber/lo"
 )
 
+// selectGroup starts an interactive group selection with fzf, if it is configured and installed
+// The builtin selection is used otherwise.
+func (c *Client) selectGroup() (string, error) {
+	printer, err := c.buildInteractiveSelectPrinter()
+	if err != nil {
+		return "", err
+	}
+
+	fzf, ok := finder.NewFzf(c.Config, "group")
+	if !ok {
+		return printer.Show()
+	}
+	result, err := fzf.Show(&finder.Page{
+		Items: lo.Map(printer.Options, func(name string, _ int) finder.Item {
+			return finder.Item{Name: name}
+		}),
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.Item, nil
+}
+
 // start an interactive group selection
 func (c *Client) buildInteractiveSelectPrinter() (*pterm.InteractiveSelectPrinter, error) {
 	// compute all selection options
@@ -23,6 +49,12 @@ func (c *Client) buildInteractiveSelectPrinter() (*pterm.InteractiveSelectPrinte
 		sort.Strings(keys)
 	case SortDesc:
 		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	case SortRecent:
+		sort.Strings(keys)
+		state.SortRecent(keys, c.State.Group.Usage)
+	case SortFrecency:
+		sort.Strings(keys)
+		state.SortFrecency(keys, c.State.Group.Usage, time.Now())
 	}
 
 	selector := pterm.DefaultInteractiveSelect.
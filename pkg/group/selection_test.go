@@ -2,6 +2,7 @@ package group
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -204,6 +205,59 @@ func Test_buildInteractiveSelectPrinter(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "should return a printer, that sorts the given group by frecency",
+			args: args{
+				Config: &config.Config{
+					Group: config.Group{
+						Selection: config.Selection{
+							Sort: SortFrecency,
+						},
+						Items: []config.GroupItem{
+							{
+								Name: "group-c",
+							},
+							{
+								Name: "group-b",
+							},
+							{
+								Name: "group-a",
+							},
+						},
+					},
+				},
+				State: &state.State{
+					Group: state.Group{
+						Usage: map[string]state.Usage{
+							"group-b": {Count: 1, Last: time.Now()},
+							"group-c": {Count: 10, Last: time.Now().Add(-30 * 24 * time.Hour)},
+						},
+					},
+				},
+			},
+			want: &pterm.InteractiveSelectPrinter{
+				TextStyle: &pterm.Style{
+					pterm.FgLightCyan,
+				},
+				DefaultText: "Please select an option",
+				Options: []string{
+					"group-c",
+					"group-b",
+					"group-a",
+				},
+				OptionStyle: &pterm.Style{
+					pterm.FgDefault,
+					pterm.BgDefault,
+				},
+				DefaultOption: "",
+				MaxHeight:     MaxSelectHeight,
+				Selector:      ">",
+				SelectorStyle: &pterm.Style{
+					pterm.FgLightMagenta,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "should return a printer, that sets the default selection to the active group",
 			args: args{
@@ -0,0 +1,76 @@
+package group
+
+import (
+	"fmt"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/context"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/samber/lo"
+)
+
+// reference points from a config path to a context
+type reference struct {
+	path    string
+	context string
+}
+
+// ValidateContexts checks, that the default, selection default and safe context of each group
+// are provided by the sources of the group
+func ValidateContexts(currentConfig *config.Config) []config.Issue {
+	var issues []config.Issue
+
+	for i, group := range currentConfig.Group.Items {
+		group := group
+		path := fmt.Sprintf("group.items[%d]", i)
+
+		references := lo.Filter([]reference{
+			{path: path + ".context.default", context: group.Context.Default},
+			{path: path + ".context.selection.default", context: group.Context.Selection.Default},
+			{path: path + ".context.safe", context: group.Context.Safe},
+		}, func(item reference, _ int) bool {
+			return len(item.context) > 0 && item.context != context.PreviousContextAlias
+		})
+		if len(references) == 0 {
+			continue
+		}
+
+		apiConfig, _, err := source.MergeGroup(currentConfig, &group, source.ExecCached)
+		if err != nil {
+			issues = append(issues, config.Issue{Path: path + ".sources", Message: err.Error()})
+			continue
+		}
+
+		for _, item := range references {
+			if _, ok := apiConfig.Contexts[item.context]; !ok {
+				issues = append(issues, config.Issue{
+					Path:    item.path,
+					Message: fmt.Sprintf("context '%s' is not provided by the sources of group '%s'", item.context, group.Name),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// ValidateConflicts reports all clusters, users and contexts, whose names are defined by more than one file of a group
+func ValidateConflicts(currentConfig *config.Config) []config.Issue {
+	var issues []config.Issue
+
+	for i, group := range currentConfig.Group.Items {
+		group := group
+		path := fmt.Sprintf("group.items[%d].sources", i)
+
+		_, conflicts, err := source.MergeGroup(currentConfig, &group, source.ExecCached)
+		if err != nil {
+			issues = append(issues, config.Issue{Path: path, Message: err.Error()})
+			continue
+		}
+		for _, conflict := range conflicts {
+			issues = append(issues, config.Issue{Path: path, Message: conflict.String()})
+		}
+	}
+
+	return issues
+}
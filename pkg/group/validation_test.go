@@ -0,0 +1,82 @@
+package group
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/context"
+)
+
+func Test_ValidateContexts(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	kubeconfigFile := filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml")
+
+	tests := []struct {
+		name  string
+		group config.GroupItem
+		want  []config.Issue
+	}{
+		{
+			name: "should not report any issue, as the default contexts are provided by the sources",
+			group: config.GroupItem{
+				Name:    "dev",
+				Sources: []string{"dev"},
+				Context: config.Context{
+					Default: "kind-dev",
+					Selection: config.Selection{
+						Default: context.PreviousContextAlias,
+					},
+				},
+			},
+		},
+		{
+			name: "should not report any issue, as there are no default contexts",
+			group: config.GroupItem{
+				Name:    "dev",
+				Sources: []string{"missing"},
+			},
+		},
+		{
+			name: "should report the default contexts, that are not provided by the sources",
+			group: config.GroupItem{
+				Name:    "dev",
+				Sources: []string{"dev"},
+				Context: config.Context{
+					Default: "kind-local",
+					Selection: config.Selection{
+						Default: "kind-dev",
+					},
+				},
+			},
+			want: []config.Issue{
+				{
+					Path:    "group.items[0].context.default",
+					Message: "context 'kind-local' is not provided by the sources of group 'dev'",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentConfig := &config.Config{
+				Group: config.Group{
+					Items: []config.GroupItem{tt.group},
+				},
+				Source: config.Source{
+					Items: []config.SourceItem{
+						{Name: "dev", Include: []string{kubeconfigFile}},
+					},
+				},
+			}
+
+			got := ValidateContexts(currentConfig)
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("group.ValidateContexts() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
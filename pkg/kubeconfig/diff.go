@@ -0,0 +1,228 @@
+package kubeconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+type Operation string
+type Kind string
+
+const (
+	OperationAdded    Operation = "added"
+	OperationRemoved  Operation = "removed"
+	OperationModified Operation = "modified"
+
+	KindCurrentContext Kind = "current-context"
+	KindCluster        Kind = "cluster"
+	KindContext        Kind = "context"
+	KindUser           Kind = "user"
+
+	Redacted = "<redacted>"
+)
+
+// Change describes a single semantic difference between two api configs
+// Modifications are reported per field, additions and removals per object.
+type Change struct {
+	Kind      Kind
+	Name      string
+	Operation Operation
+	Field     string
+	From      string
+	To        string
+	// Secret marks changes, whose values contain credentials
+	Secret bool
+}
+
+type field struct {
+	value  string
+	secret bool
+}
+
+// Diff computes all changes, that are required to turn the source into the target api config
+func Diff(source *api.Config, target *api.Config) []Change {
+	var buffer []Change
+
+	if source.CurrentContext != target.CurrentContext {
+		buffer = append(buffer, Change{
+			Kind:      KindCurrentContext,
+			Operation: OperationModified,
+			From:      source.CurrentContext,
+			To:        target.CurrentContext,
+		})
+	}
+
+	buffer = append(buffer, diffObjects(KindCluster, source.Clusters, target.Clusters, clusterFields)...)
+	buffer = append(buffer, diffObjects(KindContext, source.Contexts, target.Contexts, contextFields)...)
+	buffer = append(buffer, diffObjects(KindUser, source.AuthInfos, target.AuthInfos, authInfoFields)...)
+
+	return buffer
+}
+
+// Redact replaces the values of all changes, that contain credentials
+func Redact(changes []Change) []Change {
+	var buffer []Change
+
+	for _, change := range changes {
+		if change.Secret {
+			if len(change.From) > 0 {
+				change.From = Redacted
+			}
+			if len(change.To) > 0 {
+				change.To = Redacted
+			}
+		}
+		buffer = append(buffer, change)
+	}
+
+	return buffer
+}
+
+func diffObjects[T any](kind Kind, source map[string]T, target map[string]T, fields func(T) map[string]field) []Change {
+	var buffer []Change
+
+	var names []string
+	for name := range source {
+		names = append(names, name)
+	}
+	for name := range target {
+		if _, ok := source[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		from, inSource := source[name]
+		to, inTarget := target[name]
+
+		switch {
+		case !inSource:
+			buffer = append(buffer, Change{Kind: kind, Name: name, Operation: OperationAdded})
+		case !inTarget:
+			buffer = append(buffer, Change{Kind: kind, Name: name, Operation: OperationRemoved})
+		default:
+			buffer = append(buffer, diffFields(kind, name, fields(from), fields(to))...)
+		}
+	}
+
+	return buffer
+}
+
+func diffFields(kind Kind, name string, source map[string]field, target map[string]field) []Change {
+	var buffer []Change
+
+	var keys []string
+	for key := range source {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if source[key].value == target[key].value {
+			continue
+		}
+		buffer = append(buffer, Change{
+			Kind:      kind,
+			Name:      name,
+			Operation: OperationModified,
+			Field:     key,
+			From:      source[key].value,
+			To:        target[key].value,
+			Secret:    source[key].secret,
+		})
+	}
+
+	return buffer
+}
+
+func clusterFields(cluster *api.Cluster) map[string]field {
+	if cluster == nil {
+		cluster = api.NewCluster()
+	}
+	return map[string]field{
+		"server":                     {value: cluster.Server},
+		"tls-server-name":            {value: cluster.TLSServerName},
+		"insecure-skip-tls-verify":   {value: strconv.FormatBool(cluster.InsecureSkipTLSVerify)},
+		"certificate-authority":      {value: cluster.CertificateAuthority},
+		"certificate-authority-data": {value: digest(cluster.CertificateAuthorityData)},
+		"proxy-url":                  {value: cluster.ProxyURL},
+		"disable-compression":        {value: strconv.FormatBool(cluster.DisableCompression)},
+	}
+}
+
+func contextFields(context *api.Context) map[string]field {
+	if context == nil {
+		context = api.NewContext()
+	}
+	return map[string]field{
+		"cluster":   {value: context.Cluster},
+		"user":      {value: context.AuthInfo},
+		"namespace": {value: context.Namespace},
+	}
+}
+
+func authInfoFields(authInfo *api.AuthInfo) map[string]field {
+	if authInfo == nil {
+		authInfo = api.NewAuthInfo()
+	}
+	fields := map[string]field{
+		"client-certificate":      {value: authInfo.ClientCertificate},
+		"client-certificate-data": {value: digest(authInfo.ClientCertificateData)},
+		"client-key":              {value: authInfo.ClientKey},
+		"client-key-data":         {value: string(authInfo.ClientKeyData), secret: true},
+		"token":                   {value: authInfo.Token, secret: true},
+		"token-file":              {value: authInfo.TokenFile},
+		"as":                      {value: authInfo.Impersonate},
+		"as-uid":                  {value: authInfo.ImpersonateUID},
+		"as-groups":               {value: strings.Join(authInfo.ImpersonateGroups, ",")},
+		"username":                {value: authInfo.Username},
+		"password":                {value: authInfo.Password, secret: true},
+		"auth-provider":           {},
+		"auth-provider-config":    {secret: true},
+		"exec-command":            {},
+		"exec-args":               {},
+		"exec-env":                {secret: true},
+	}
+
+	if authInfo.AuthProvider != nil {
+		fields["auth-provider"] = field{value: authInfo.AuthProvider.Name}
+		fields["auth-provider-config"] = field{value: joinMap(authInfo.AuthProvider.Config), secret: true}
+	}
+
+	if authInfo.Exec != nil {
+		env := map[string]string{}
+		for _, item := range authInfo.Exec.Env {
+			env[item.Name] = item.Value
+		}
+		fields["exec-command"] = field{value: authInfo.Exec.Command}
+		fields["exec-args"] = field{value: strings.Join(authInfo.Exec.Args, " ")}
+		fields["exec-env"] = field{value: joinMap(env), secret: true}
+	}
+
+	return fields
+}
+
+// digest shortens binary data like certificates to a comparable fingerprint
+func digest(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("sha256:%s", hex.EncodeToString(sum[:])[:12])
+}
+
+func joinMap(values map[string]string) string {
+	var buffer []string
+	for key, value := range values {
+		buffer = append(buffer, key+"="+value)
+	}
+	sort.Strings(buffer)
+	return strings.Join(buffer, ",")
+}
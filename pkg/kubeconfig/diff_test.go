@@ -0,0 +1,119 @@
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Diff(t *testing.T) {
+	type args struct {
+		source *api.Config
+		target *api.Config
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Change
+	}{
+		{
+			name: "should not report any changes for equal configs",
+			args: args{
+				source: &api.Config{
+					CurrentContext: "kind",
+					Contexts: map[string]*api.Context{
+						"kind": {Cluster: "kind", AuthInfo: "kind", LocationOfOrigin: "a.yaml"},
+					},
+				},
+				target: &api.Config{
+					CurrentContext: "kind",
+					Contexts: map[string]*api.Context{
+						"kind": {Cluster: "kind", AuthInfo: "kind", LocationOfOrigin: "b.yaml"},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "should report added, removed and modified objects",
+			args: args{
+				source: &api.Config{
+					CurrentContext: "kind",
+					Clusters: map[string]*api.Cluster{
+						"kind": {Server: "https://127.0.0.1:6443"},
+					},
+					Contexts: map[string]*api.Context{
+						"kind": {Cluster: "kind", AuthInfo: "kind"},
+					},
+					AuthInfos: map[string]*api.AuthInfo{
+						"kind": {Token: "secret"},
+					},
+				},
+				target: &api.Config{
+					CurrentContext: "prod",
+					Clusters: map[string]*api.Cluster{
+						"kind": {Server: "https://127.0.0.1:6444"},
+					},
+					Contexts: map[string]*api.Context{
+						"prod": {Cluster: "kind", AuthInfo: "kind"},
+					},
+					AuthInfos: map[string]*api.AuthInfo{
+						"kind": {Token: "changed"},
+					},
+				},
+			},
+			want: []Change{
+				{Kind: KindCurrentContext, Operation: OperationModified, From: "kind", To: "prod"},
+				{Kind: KindCluster, Name: "kind", Operation: OperationModified, Field: "server", From: "https://127.0.0.1:6443", To: "https://127.0.0.1:6444"},
+				{Kind: KindContext, Name: "kind", Operation: OperationRemoved},
+				{Kind: KindContext, Name: "prod", Operation: OperationAdded},
+				{Kind: KindUser, Name: "kind", Operation: OperationModified, Field: "token", From: "secret", To: "changed", Secret: true},
+			},
+		},
+		{
+			name: "should report modified certificates as fingerprints",
+			args: args{
+				source: &api.Config{
+					Clusters: map[string]*api.Cluster{
+						"kind": {CertificateAuthorityData: []byte("a")},
+					},
+				},
+				target: &api.Config{
+					Clusters: map[string]*api.Cluster{
+						"kind": {CertificateAuthorityData: []byte("b")},
+					},
+				},
+			},
+			want: []Change{
+				{Kind: KindCluster, Name: "kind", Operation: OperationModified, Field: "certificate-authority-data", From: "sha256:ca978112ca1b", To: "sha256:3e23e8160039"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.args.source, tt.args.target)
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("kubeconfig.Diff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Redact(t *testing.T) {
+	changes := []Change{
+		{Kind: KindUser, Name: "kind", Operation: OperationModified, Field: "token", From: "secret", To: "", Secret: true},
+		{Kind: KindUser, Name: "kind", Operation: OperationModified, Field: "username", From: "a", To: "b"},
+	}
+	want := []Change{
+		{Kind: KindUser, Name: "kind", Operation: OperationModified, Field: "token", From: Redacted, To: "", Secret: true},
+		{Kind: KindUser, Name: "kind", Operation: OperationModified, Field: "username", From: "a", To: "b"},
+	}
+
+	got := Redact(changes)
+	if !cmp.Equal(want, got) {
+		diff := cmp.Diff(want, got)
+		t.Errorf("kubeconfig.Redact() mismatch (-want +got):\n%s", diff)
+	}
+}
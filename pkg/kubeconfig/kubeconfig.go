@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/file"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
@@ -27,6 +29,44 @@ func Read(file *os.File) (*api.Config, error) {
 	return buffer, nil
 }
 
+// ReadFile reads the kubeconfig at the given path and resolves its relative paths against the directory of the file
+func ReadFile(path string) (*api.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open kubeconfig, err: '%w'", err)
+	}
+	defer file.Close()
+
+	apiConfig, err := Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	origin, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute absolute path, err: '%w'", err)
+	}
+	SetLocationOfOrigin(apiConfig, origin)
+	err = clientcmd.ResolveLocalPaths(apiConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve local paths, err: '%w'", err)
+	}
+	return apiConfig, nil
+}
+
+// SetLocationOfOrigin sets the given file as origin of all clusters, users and contexts of the api config
+func SetLocationOfOrigin(apiConfig *api.Config, file string) {
+	for _, cluster := range apiConfig.Clusters {
+		cluster.LocationOfOrigin = file
+	}
+	for _, authInfo := range apiConfig.AuthInfos {
+		authInfo.LocationOfOrigin = file
+	}
+	for _, context := range apiConfig.Contexts {
+		context.LocationOfOrigin = file
+	}
+}
+
 func Write(file *os.File, apiConfig *api.Config) error {
 	log := logger.New()
 
@@ -55,20 +95,24 @@ func Write(file *os.File, apiConfig *api.Config) error {
 	return nil
 }
 
-func Merge(files ...*os.File) (*api.Config, error) {
-	var buffer []string
+// WriteFile atomically replaces the kubeconfig at the given path with the api config
+func WriteFile(path string, apiConfig *api.Config) error {
+	log := logger.New()
 
-	for _, file := range files {
-		buffer = append(buffer, file.Name())
+	if apiConfig == nil {
+		return fmt.Errorf("invalid api config")
 	}
 
-	loadingRules := clientcmd.ClientConfigLoadingRules{
-		Precedence: buffer,
+	buffer, err := clientcmd.Write(*apiConfig)
+	if err != nil {
+		return fmt.Errorf("persist new kubeconfig, err: '%w'", err)
 	}
 
-	matches, err := loadingRules.Load()
+	err = file.WriteAtomic(path, buffer, 0600)
 	if err != nil {
-		return nil, fmt.Errorf("could not merge kubeconfig files, err: '%w'", err)
+		return err
 	}
-	return matches, nil
+
+	log.Debug("wrote kubeconfig", log.Args("file", path))
+	return nil
 }
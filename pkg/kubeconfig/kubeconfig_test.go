@@ -168,87 +168,40 @@ func Test_Write(t *testing.T) {
 	}
 }
 
-func Test_Merge(t *testing.T) {
-	type args struct {
-		files []*os.File
-	}
+func Test_ReadFile(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	testdata := filepath.Join(caller, "..", "testdata")
+
 	tests := []struct {
 		name    string
-		args    args
-		want    *api.Config
+		path    string
+		want    string
 		wantErr bool
 	}{
 		{
-			name: "should merge the kubeconfig successfully",
-			args: args{
-				files: func() []*os.File {
-					var buffer []*os.File
-
-					_, caller, _, _ := runtime.Caller(0)
-					filenames := []string{
-						"03-kontext-merge-1.yaml",
-						"04-kontext-merge-2.yaml",
-						"05-kontext-merge-3.yaml",
-					}
-
-					for _, filename := range filenames {
-						path := filepath.Join(caller, "..", "testdata", filename)
-						file, err := os.Open(path)
-						if err != nil {
-							t.Errorf("%v", err)
-						}
-						buffer = append(buffer, file)
-					}
-
-					return buffer
-				}(),
-			},
-			wantErr: false,
+			name: "should read the kubeconfig and resolve its relative paths",
+			path: filepath.Join(testdata, "07-relative-kubeconfig.yaml"),
+			want: filepath.Join(testdata, "06-certificate-authority.crt"),
 		},
 		{
-			name: "should merge the kubeconfig successfully, even if a kubeconfig file is invalid",
-			args: args{
-				files: func() []*os.File {
-					var buffer []*os.File
-
-					_, caller, _, _ := runtime.Caller(0)
-					filenames := []string{
-						"02-invalid-kubeconfig.yaml",
-						"04-kontext-merge-2.yaml",
-						"05-kontext-merge-3.yaml",
-					}
-
-					for _, filename := range filenames {
-						path := filepath.Join(caller, "..", "testdata", filename)
-						file, err := os.Open(path)
-						if err != nil {
-							t.Errorf("%v", err)
-						}
-						buffer = append(buffer, file)
-					}
-
-					return buffer
-				}(),
-			},
-			wantErr: false,
+			name:    "should throw an error, as the file does not exist",
+			path:    filepath.Join(testdata, "missing.yaml"),
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Merge(tt.args.files...)
+			got, err := ReadFile(tt.path)
 			if !tt.wantErr && err != nil {
 				t.Errorf("unexpected error, err: '%v'", err)
 			}
+
 			if tt.wantErr && err == nil {
 				t.Errorf("expected error, got: '%v'", err)
 			}
 
-			if !tt.wantErr && tt.want != nil && got == nil {
-				t.Errorf("want: '%v', got: '%v'", tt.want, got)
-			}
-
-			if !tt.wantErr && got.CurrentContext == "" {
-				t.Errorf("want: '%v', got: '%v'", tt.want, got)
+			if !tt.wantErr && got.Clusters["kind-dev"].CertificateAuthority != tt.want {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got.Clusters["kind-dev"].CertificateAuthority)
 			}
 		})
 	}
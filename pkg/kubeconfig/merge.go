@@ -0,0 +1,178 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Conflict describes a name, that is defined by more than one file with different values
+type Conflict struct {
+	Kind  Kind
+	Name  string
+	Files []string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s '%s' is defined by %d files with different values, the first one wins: %v", c.Kind, c.Name, len(c.Files), c.Files)
+}
+
+// Rename renames all clusters, users and contexts of the api config and updates all references to them
+func Rename(apiConfig *api.Config, rename func(name string) (string, error)) error {
+	clusters, err := renameKeys(apiConfig.Clusters, rename)
+	if err != nil {
+		return err
+	}
+	users, err := renameKeys(apiConfig.AuthInfos, rename)
+	if err != nil {
+		return err
+	}
+	contexts, err := renameKeys(apiConfig.Contexts, rename)
+	if err != nil {
+		return err
+	}
+
+	for _, context := range apiConfig.Contexts {
+		if name, ok := clusters[context.Cluster]; ok {
+			context.Cluster = name
+		}
+		if name, ok := users[context.AuthInfo]; ok {
+			context.AuthInfo = name
+		}
+	}
+	if name, ok := contexts[apiConfig.CurrentContext]; ok {
+		apiConfig.CurrentContext = name
+	}
+
+	apiConfig.Clusters = lo.MapKeys(apiConfig.Clusters, func(_ *api.Cluster, key string) string { return clusters[key] })
+	apiConfig.AuthInfos = lo.MapKeys(apiConfig.AuthInfos, func(_ *api.AuthInfo, key string) string { return users[key] })
+	apiConfig.Contexts = lo.MapKeys(apiConfig.Contexts, func(_ *api.Context, key string) string { return contexts[key] })
+	return nil
+}
+
+// RenameContexts renames the given contexts of the api config, the keys of the map are the current names.
+// Clusters and users keep their names.
+func RenameContexts(apiConfig *api.Config, names map[string]string) error {
+	for name := range names {
+		if _, ok := apiConfig.Contexts[name]; !ok {
+			return fmt.Errorf("could not rename context '%s', it does not exist", name)
+		}
+	}
+
+	contexts, err := renameKeys(apiConfig.Contexts, func(name string) (string, error) {
+		return lo.ValueOr(names, name, name), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if name, ok := contexts[apiConfig.CurrentContext]; ok {
+		apiConfig.CurrentContext = name
+	}
+	apiConfig.Contexts = lo.MapKeys(apiConfig.Contexts, func(_ *api.Context, key string) string { return contexts[key] })
+	return nil
+}
+
+// renameKeys computes the new name for each key, it fails if two keys would share the same name
+func renameKeys[T any](entries map[string]T, rename func(name string) (string, error)) (map[string]string, error) {
+	buffer := map[string]string{}
+	seen := map[string]string{}
+
+	for key := range entries {
+		name, err := rename(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(name) == 0 {
+			return nil, fmt.Errorf("could not rename '%s', the new name is empty", key)
+		}
+		if previous, ok := seen[name]; ok {
+			return nil, fmt.Errorf("could not rename '%s', the new name '%s' is already used by '%s'", key, name, previous)
+		}
+		seen[name] = key
+		buffer[key] = name
+	}
+
+	return buffer, nil
+}
+
+// MergeConfigs merges the given api configs with the precedence of clientcmd, the first definition of a name wins
+// All names, that are defined more than once with different values, are returned as conflicts.
+func MergeConfigs(apiConfigs ...*api.Config) (*api.Config, []Conflict) {
+	buffer := api.NewConfig()
+	var conflicts []Conflict
+
+	for _, apiConfig := range apiConfigs {
+		if len(buffer.CurrentContext) == 0 {
+			buffer.CurrentContext = apiConfig.CurrentContext
+		}
+		for key, value := range apiConfig.Extensions {
+			if _, ok := buffer.Extensions[key]; !ok {
+				buffer.Extensions[key] = value
+			}
+		}
+	}
+
+	conflicts = append(conflicts, mergeEntries(KindCluster, buffer.Clusters, apiConfigs, func(apiConfig *api.Config) map[string]*api.Cluster {
+		return apiConfig.Clusters
+	}, func(item *api.Cluster) (string, any) {
+		clone := item.DeepCopy()
+		clone.LocationOfOrigin = ""
+		return item.LocationOfOrigin, clone
+	})...)
+	conflicts = append(conflicts, mergeEntries(KindUser, buffer.AuthInfos, apiConfigs, func(apiConfig *api.Config) map[string]*api.AuthInfo {
+		return apiConfig.AuthInfos
+	}, func(item *api.AuthInfo) (string, any) {
+		clone := item.DeepCopy()
+		clone.LocationOfOrigin = ""
+		return item.LocationOfOrigin, clone
+	})...)
+	conflicts = append(conflicts, mergeEntries(KindContext, buffer.Contexts, apiConfigs, func(apiConfig *api.Config) map[string]*api.Context {
+		return apiConfig.Contexts
+	}, func(item *api.Context) (string, any) {
+		clone := item.DeepCopy()
+		clone.LocationOfOrigin = ""
+		return item.LocationOfOrigin, clone
+	})...)
+
+	return buffer, conflicts
+}
+
+// mergeEntries copies the first definition of each name into the target and reports all conflicting definitions
+// The origin function returns the file and a comparable copy of the given entry.
+func mergeEntries[T any](kind Kind, target map[string]T, apiConfigs []*api.Config, entries func(*api.Config) map[string]T, origin func(T) (string, any)) []Conflict {
+	var buffer []Conflict
+	files := map[string][]string{}
+	conflicting := map[string]bool{}
+
+	for _, apiConfig := range apiConfigs {
+		for name, entry := range entries(apiConfig) {
+			file, value := origin(entry)
+			files[name] = append(files[name], file)
+
+			first, ok := target[name]
+			if !ok {
+				target[name] = entry
+				continue
+			}
+			if _, firstValue := origin(first); !reflect.DeepEqual(firstValue, value) {
+				conflicting[name] = true
+			}
+		}
+	}
+
+	names := lo.Keys(conflicting)
+	sort.Strings(names)
+	for _, name := range names {
+		buffer = append(buffer, Conflict{
+			Kind:  kind,
+			Name:  name,
+			Files: lo.Uniq(files[name]),
+		})
+	}
+
+	return buffer
+}
@@ -0,0 +1,207 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Rename(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiConfig *api.Config
+		rename    func(name string) (string, error)
+		want      *api.Config
+		wantErr   bool
+	}{
+		{
+			name: "should rename all entries and their references",
+			apiConfig: &api.Config{
+				CurrentContext: "admin@cluster",
+				Clusters:       map[string]*api.Cluster{"cluster": {Server: "https://127.0.0.1"}},
+				AuthInfos:      map[string]*api.AuthInfo{"admin": {Token: "kontext"}},
+				Contexts: map[string]*api.Context{
+					"admin@cluster": {Cluster: "cluster", AuthInfo: "admin"},
+					"external":      {Cluster: "external", AuthInfo: "external"},
+				},
+			},
+			rename: func(name string) (string, error) {
+				return "customer-" + name, nil
+			},
+			want: &api.Config{
+				CurrentContext: "customer-admin@cluster",
+				Clusters:       map[string]*api.Cluster{"customer-cluster": {Server: "https://127.0.0.1"}},
+				AuthInfos:      map[string]*api.AuthInfo{"customer-admin": {Token: "kontext"}},
+				Contexts: map[string]*api.Context{
+					"customer-admin@cluster": {Cluster: "customer-cluster", AuthInfo: "customer-admin"},
+					// references to entries of other files are kept
+					"customer-external": {Cluster: "external", AuthInfo: "external"},
+				},
+			},
+		},
+		{
+			name: "should throw an error, as two contexts would share the same name",
+			apiConfig: &api.Config{
+				Contexts: map[string]*api.Context{
+					"dev":  {},
+					"prod": {},
+				},
+			},
+			rename: func(name string) (string, error) {
+				return "customer", nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as the rename function fails",
+			apiConfig: &api.Config{
+				Contexts: map[string]*api.Context{"dev": {}},
+			},
+			rename: func(name string) (string, error) {
+				return "", fmt.Errorf("invalid name")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Rename(tt.apiConfig, tt.rename)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want, tt.apiConfig) {
+				diff := cmp.Diff(tt.want, tt.apiConfig)
+				t.Errorf("kubeconfig.Rename() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_RenameContexts(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiConfig *api.Config
+		names     map[string]string
+		want      *api.Config
+		wantErr   bool
+	}{
+		{
+			name: "should rename the given contexts and keep the clusters and users",
+			apiConfig: &api.Config{
+				CurrentContext: "kind-kind",
+				Clusters:       map[string]*api.Cluster{"kind-kind": {}},
+				Contexts: map[string]*api.Context{
+					"kind-kind": {Cluster: "kind-kind"},
+					"other":     {},
+				},
+			},
+			names: map[string]string{"kind-kind": "kind-dev"},
+			want: &api.Config{
+				CurrentContext: "kind-dev",
+				Clusters:       map[string]*api.Cluster{"kind-kind": {}},
+				Contexts: map[string]*api.Context{
+					"kind-dev": {Cluster: "kind-kind"},
+					"other":    {},
+				},
+			},
+		},
+		{
+			name: "should throw an error, as the context does not exist",
+			apiConfig: &api.Config{
+				Contexts: map[string]*api.Context{"dev": {}},
+			},
+			names:   map[string]string{"prod": "stage"},
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as the new name is already used",
+			apiConfig: &api.Config{
+				Contexts: map[string]*api.Context{"dev": {}, "prod": {}},
+			},
+			names:   map[string]string{"prod": "dev"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RenameContexts(tt.apiConfig, tt.names)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want, tt.apiConfig) {
+				diff := cmp.Diff(tt.want, tt.apiConfig)
+				t.Errorf("kubeconfig.RenameContexts() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_MergeConfigs(t *testing.T) {
+	tests := []struct {
+		name          string
+		apiConfigs    []*api.Config
+		want          *api.Config
+		wantConflicts []Conflict
+	}{
+		{
+			name: "should merge all entries and report conflicting names, the first definition wins",
+			apiConfigs: []*api.Config{
+				{
+					Clusters: map[string]*api.Cluster{"cluster": {LocationOfOrigin: "a.yaml", Server: "https://a"}},
+					Contexts: map[string]*api.Context{"dev": {LocationOfOrigin: "a.yaml", Cluster: "cluster"}},
+				},
+				{
+					CurrentContext: "dev",
+					Clusters:       map[string]*api.Cluster{"cluster": {LocationOfOrigin: "b.yaml", Server: "https://b"}},
+					Contexts: map[string]*api.Context{
+						// identical definitions are no conflict
+						"dev":  {LocationOfOrigin: "b.yaml", Cluster: "cluster"},
+						"prod": {LocationOfOrigin: "b.yaml", Cluster: "cluster"},
+					},
+				},
+			},
+			want: &api.Config{
+				CurrentContext: "dev",
+				Preferences:    api.Preferences{Extensions: map[string]runtime.Object{}},
+				Clusters:       map[string]*api.Cluster{"cluster": {LocationOfOrigin: "a.yaml", Server: "https://a"}},
+				AuthInfos:      map[string]*api.AuthInfo{},
+				Contexts: map[string]*api.Context{
+					"dev":  {LocationOfOrigin: "a.yaml", Cluster: "cluster"},
+					"prod": {LocationOfOrigin: "b.yaml", Cluster: "cluster"},
+				},
+				Extensions: map[string]runtime.Object{},
+			},
+			wantConflicts: []Conflict{
+				{Kind: KindCluster, Name: "cluster", Files: []string{"a.yaml", "b.yaml"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := MergeConfigs(tt.apiConfigs...)
+
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("kubeconfig.MergeConfigs() mismatch (-want +got):\n%s", diff)
+			}
+
+			if !cmp.Equal(tt.wantConflicts, conflicts) {
+				diff := cmp.Diff(tt.wantConflicts, conflicts)
+				t.Errorf("kubeconfig.MergeConfigs() conflicts mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
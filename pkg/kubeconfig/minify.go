@@ -0,0 +1,57 @@
+package kubeconfig
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Minify returns a copy of the api config, that only contains the given context, its cluster and its user.
+// The given context becomes the current context of the copy.
+func Minify(apiConfig *api.Config, contextName string) (*api.Config, error) {
+	if apiConfig == nil {
+		return nil, fmt.Errorf("invalid api config")
+	}
+
+	buffer := apiConfig.DeepCopy()
+	buffer.CurrentContext = contextName
+
+	err := api.MinifyConfig(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("could not minify kubeconfig, err: '%w'", err)
+	}
+	return buffer, nil
+}
+
+// Prune returns a copy of the api config, that only contains the clusters and users, which are referred by a context
+func Prune(apiConfig *api.Config) *api.Config {
+	buffer := apiConfig.DeepCopy()
+	clusters := map[string]*api.Cluster{}
+	authInfos := map[string]*api.AuthInfo{}
+
+	for _, context := range buffer.Contexts {
+		if cluster, ok := buffer.Clusters[context.Cluster]; ok {
+			clusters[context.Cluster] = cluster
+		}
+		if authInfo, ok := buffer.AuthInfos[context.AuthInfo]; ok {
+			authInfos[context.AuthInfo] = authInfo
+		}
+	}
+	if _, ok := buffer.Contexts[buffer.CurrentContext]; !ok {
+		buffer.CurrentContext = ""
+	}
+
+	buffer.Clusters = clusters
+	buffer.AuthInfos = authInfos
+	return buffer
+}
+
+// Flatten replaces all certificates and keys, that are referred by a file path, with their inline data,
+// so the api config does not depend on any other file
+func Flatten(apiConfig *api.Config) error {
+	err := api.FlattenConfig(apiConfig)
+	if err != nil {
+		return fmt.Errorf("could not flatten kubeconfig, err: '%w'", err)
+	}
+	return nil
+}
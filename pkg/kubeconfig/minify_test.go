@@ -0,0 +1,187 @@
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Minify(t *testing.T) {
+	apiConfig := &api.Config{
+		CurrentContext: "dev",
+		Clusters: map[string]*api.Cluster{
+			"dev":  {Server: "https://127.0.0.1"},
+			"prod": {Server: "https://127.0.0.2"},
+		},
+		AuthInfos: map[string]*api.AuthInfo{
+			"dev":  {Token: "dev"},
+			"prod": {Token: "prod"},
+		},
+		Contexts: map[string]*api.Context{
+			"dev":     {Cluster: "dev", AuthInfo: "dev"},
+			"prod":    {Cluster: "prod", AuthInfo: "prod", Namespace: "kube-system"},
+			"missing": {Cluster: "missing", AuthInfo: "prod"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		contextName string
+		want        *api.Config
+		wantErr     bool
+	}{
+		{
+			name:        "should only keep the context, its cluster and its user",
+			contextName: "prod",
+			want: &api.Config{
+				CurrentContext: "prod",
+				Clusters:       map[string]*api.Cluster{"prod": {Server: "https://127.0.0.2"}},
+				AuthInfos:      map[string]*api.AuthInfo{"prod": {Token: "prod"}},
+				Contexts:       map[string]*api.Context{"prod": {Cluster: "prod", AuthInfo: "prod", Namespace: "kube-system"}},
+			},
+		},
+		{
+			name:        "should throw an error, as the context does not exist",
+			contextName: "stage",
+			wantErr:     true,
+		},
+		{
+			name:        "should throw an error, as the cluster of the context does not exist",
+			contextName: "missing",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Minify(apiConfig, tt.contextName)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("kubeconfig.Minify() mismatch (-want +got):\n%s", diff)
+			}
+
+			// the given api config must not be modified
+			if apiConfig.CurrentContext != "dev" || len(apiConfig.Contexts) != 3 {
+				t.Errorf("kubeconfig.Minify() modified the given api config")
+			}
+		})
+	}
+}
+
+func Test_Prune(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiConfig *api.Config
+		want      *api.Config
+	}{
+		{
+			name: "should remove all clusters and users, that are not referred by a context",
+			apiConfig: &api.Config{
+				CurrentContext: "dev",
+				Clusters: map[string]*api.Cluster{
+					"dev":    {Server: "https://127.0.0.1"},
+					"unused": {Server: "https://127.0.0.2"},
+				},
+				AuthInfos: map[string]*api.AuthInfo{
+					"dev":    {Token: "dev"},
+					"unused": {Token: "unused"},
+				},
+				Contexts: map[string]*api.Context{
+					"dev":     {Cluster: "dev", AuthInfo: "dev"},
+					"missing": {Cluster: "missing", AuthInfo: "missing"},
+				},
+			},
+			want: &api.Config{
+				CurrentContext: "dev",
+				Clusters:       map[string]*api.Cluster{"dev": {Server: "https://127.0.0.1"}},
+				AuthInfos:      map[string]*api.AuthInfo{"dev": {Token: "dev"}},
+				Contexts: map[string]*api.Context{
+					"dev":     {Cluster: "dev", AuthInfo: "dev"},
+					"missing": {Cluster: "missing", AuthInfo: "missing"},
+				},
+			},
+		},
+		{
+			name: "should reset the current context, as it does not exist",
+			apiConfig: &api.Config{
+				CurrentContext: "prod",
+				Contexts:       map[string]*api.Context{"dev": {}},
+			},
+			want: &api.Config{
+				Clusters:  map[string]*api.Cluster{},
+				AuthInfos: map[string]*api.AuthInfo{},
+				Contexts:  map[string]*api.Context{"dev": {}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Prune(tt.apiConfig)
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("kubeconfig.Prune() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Flatten(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	certificateAuthority := filepath.Join(caller, "..", "testdata", "06-certificate-authority.crt")
+	data, err := os.ReadFile(certificateAuthority)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := []struct {
+		name      string
+		apiConfig *api.Config
+		want      *api.Config
+		wantErr   bool
+	}{
+		{
+			name: "should inline the certificate authority",
+			apiConfig: &api.Config{
+				Clusters: map[string]*api.Cluster{"dev": {CertificateAuthority: certificateAuthority}},
+			},
+			want: &api.Config{
+				Clusters: map[string]*api.Cluster{"dev": {CertificateAuthorityData: data}},
+			},
+		},
+		{
+			name: "should throw an error, as the client key does not exist",
+			apiConfig: &api.Config{
+				AuthInfos: map[string]*api.AuthInfo{"dev": {ClientKey: filepath.Join(t.TempDir(), "missing.key")}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Flatten(tt.apiConfig)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want, tt.apiConfig) {
+				diff := cmp.Diff(tt.want, tt.apiConfig)
+				t.Errorf("kubeconfig.Flatten() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
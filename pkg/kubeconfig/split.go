@@ -0,0 +1,76 @@
+package kubeconfig
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultSplitTemplate names each file after its context
+const DefaultSplitTemplate = "{{ .Context }}.yaml"
+
+// SplitData is passed to the file name template of Split, all values are safe to be used as file names
+type SplitData struct {
+	Context   string
+	Cluster   string
+	User      string
+	Namespace string
+}
+
+// unsafeCharacters are replaced within the values of the file name template
+var unsafeCharacters = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+// Split creates one api config per context, that only contains the context, its cluster and its user.
+// The result is keyed by the relative file name, that the template computes for each context.
+func Split(apiConfig *api.Config, fileTemplate string) (map[string]*api.Config, error) {
+	instance, err := template.New("split").Option("missingkey=error").Parse(fileTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse file name template, err: '%w'", err)
+	}
+
+	// iterate in a stable order, so duplicate file names are always reported for the same contexts
+	names := make([]string, 0, len(apiConfig.Contexts))
+	for name := range apiConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buffer := map[string]*api.Config{}
+	seen := map[string]string{}
+	for _, name := range names {
+		context := apiConfig.Contexts[name]
+
+		var file bytes.Buffer
+		err := instance.Execute(&file, SplitData{
+			Context:   unsafeCharacters.Replace(name),
+			Cluster:   unsafeCharacters.Replace(context.Cluster),
+			User:      unsafeCharacters.Replace(context.AuthInfo),
+			Namespace: unsafeCharacters.Replace(context.Namespace),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not execute file name template, err: '%w'", err)
+		}
+
+		path := filepath.Clean(file.String())
+		if len(file.String()) == 0 || filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid file name '%s' for context '%s', it has to be a relative path within the output directory", file.String(), name)
+		}
+		if previous, ok := seen[path]; ok {
+			return nil, fmt.Errorf("contexts '%s' and '%s' would be written to the same file '%s'", previous, name, path)
+		}
+		seen[path] = name
+
+		minified, err := Minify(apiConfig, name)
+		if err != nil {
+			return nil, err
+		}
+		buffer[path] = minified
+	}
+
+	return buffer, nil
+}
@@ -0,0 +1,105 @@
+package kubeconfig
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Split(t *testing.T) {
+	apiConfig := &api.Config{
+		CurrentContext: "dev",
+		Clusters: map[string]*api.Cluster{
+			"dev":  {Server: "https://127.0.0.1"},
+			"prod": {Server: "https://127.0.0.2"},
+		},
+		AuthInfos: map[string]*api.AuthInfo{
+			"admin": {Token: "admin"},
+		},
+		Contexts: map[string]*api.Context{
+			"dev":                                  {Cluster: "dev", AuthInfo: "admin"},
+			"arn:aws:eks:eu-central-1:1:cluster/a": {Cluster: "prod", AuthInfo: "admin", Namespace: "kube-system"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		apiConfig    *api.Config
+		fileTemplate string
+		want         []string
+		wantErr      bool
+	}{
+		{
+			name:         "should split the kubeconfig into one file per context and replace unsafe characters",
+			apiConfig:    apiConfig,
+			fileTemplate: DefaultSplitTemplate,
+			want:         []string{"arn_aws_eks_eu-central-1_1_cluster_a.yaml", "dev.yaml"},
+		},
+		{
+			name:         "should split the kubeconfig into subdirectories",
+			apiConfig:    apiConfig,
+			fileTemplate: "{{ .Cluster }}/{{ .Context }}.yaml",
+			want: []string{
+				filepath.Join("dev", "dev.yaml"),
+				filepath.Join("prod", "arn_aws_eks_eu-central-1_1_cluster_a.yaml"),
+			},
+		},
+		{
+			name:         "should throw an error, as two contexts would be written to the same file",
+			apiConfig:    apiConfig,
+			fileTemplate: "{{ .User }}.yaml",
+			wantErr:      true,
+		},
+		{
+			name:         "should throw an error, as the file would be written outside of the output directory",
+			apiConfig:    apiConfig,
+			fileTemplate: "../{{ .Context }}.yaml",
+			wantErr:      true,
+		},
+		{
+			name:         "should throw an error, as the template refers to an unknown field",
+			apiConfig:    apiConfig,
+			fileTemplate: "{{ .Group }}.yaml",
+			wantErr:      true,
+		},
+		{
+			name: "should throw an error, as the cluster of a context does not exist",
+			apiConfig: &api.Config{
+				Contexts: map[string]*api.Context{"dev": {Cluster: "dev"}},
+			},
+			fileTemplate: DefaultSplitTemplate,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Split(tt.apiConfig, tt.fileTemplate)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			files := lo.Keys(got)
+			sort.Strings(files)
+			if !cmp.Equal(tt.want, files) {
+				diff := cmp.Diff(tt.want, files)
+				t.Errorf("kubeconfig.Split() mismatch (-want +got):\n%s", diff)
+			}
+			for file, apiConfig := range got {
+				if len(apiConfig.Contexts) != 1 || len(apiConfig.Clusters) != 1 || len(apiConfig.AuthInfos) != 1 {
+					t.Errorf("want a single context, cluster and user within '%s', got: '%v'", file, apiConfig)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package label
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/file"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Store maps each context name to the labels, that have been set with `kontext label context`
+type Store map[string]map[string]string
+
+// Change sets or removes the labels of a single context
+type Change struct {
+	Set    map[string]string
+	Remove []string
+}
+
+// Read reads the label file, a missing file is an empty store
+func Read(currentConfig *config.Config) (Store, error) {
+	log := logger.New()
+	store := Store{}
+
+	data, err := os.ReadFile(currentConfig.Label.File)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read label file, err: '%w'", err)
+	}
+	if len(data) == 0 {
+		return store, nil
+	}
+
+	err = json.Unmarshal(data, &store)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse label file '%s', err: '%w'", currentConfig.Label.File, err)
+	}
+	log.Trace("read label file", log.Args("path", currentConfig.Label.File))
+
+	return store, nil
+}
+
+// Write replaces the label file with the given store
+func Write(currentConfig *config.Config, store Store) error {
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(currentConfig.Label.File), 0755)
+	if err != nil {
+		return fmt.Errorf("could not create label directory, err: '%w'", err)
+	}
+	err = file.WriteAtomic(currentConfig.Label.File, data, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write label file, err: '%w'", err)
+	}
+
+	return nil
+}
+
+// ParseChange parses arguments like kubectl label does, key=value sets a label and key- removes it
+func ParseChange(args []string) (*Change, error) {
+	change := &Change{
+		Set: map[string]string{},
+	}
+
+	for _, arg := range args {
+		if key, ok := strings.CutSuffix(arg, "-"); ok && !strings.Contains(arg, "=") {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid label key '%s', err: '%s'", key, strings.Join(errs, ", "))
+			}
+			change.Remove = append(change.Remove, key)
+			continue
+		}
+
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label '%s', expected key=value or key-", arg)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key '%s', err: '%s'", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label value '%s', err: '%s'", value, strings.Join(errs, ", "))
+		}
+		change.Set[key] = value
+	}
+
+	return change, nil
+}
+
+// Apply applies the change to the labels of the given context, contexts without labels are removed from the store
+func (s Store) Apply(contextName string, change *Change) {
+	labels := s[contextName]
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	for key, value := range change.Set {
+		labels[key] = value
+	}
+	for _, key := range change.Remove {
+		delete(labels, key)
+	}
+
+	if len(labels) == 0 {
+		delete(s, contextName)
+		return
+	}
+	s[contextName] = labels
+}
@@ -0,0 +1,124 @@
+package label
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+)
+
+func Test_ParseChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    *Change
+		wantErr bool
+	}{
+		{
+			name: "should parse all labels, that shall be set or removed",
+			args: []string{"env=prod", "team=a", "owner-", "kontext.io/tier=gold"},
+			want: &Change{
+				Set:    map[string]string{"env": "prod", "team": "a", "kontext.io/tier": "gold"},
+				Remove: []string{"owner"},
+			},
+		},
+		{
+			name: "should set an empty value",
+			args: []string{"env="},
+			want: &Change{
+				Set: map[string]string{"env": ""},
+			},
+		},
+		{
+			name:    "should throw an error, as the value ends with a dash",
+			args:    []string{"env=prod-"},
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the label has no value",
+			args:    []string{"env"},
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the key is invalid",
+			args:    []string{"Env Prod=dev"},
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the value is invalid",
+			args:    []string{"env=prod dev"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChange(tt.args)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("label.ParseChange() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Apply(t *testing.T) {
+	store := Store{
+		"kind-dev": {"env": "dev", "owner": "a"},
+	}
+
+	store.Apply("kind-dev", &Change{Set: map[string]string{"env": "staging"}, Remove: []string{"owner"}})
+	store.Apply("kind-prod", &Change{Set: map[string]string{"env": "prod"}})
+	store.Apply("kind-prod", &Change{Remove: []string{"env"}})
+	store.Apply("kind-local", &Change{Set: map[string]string{"team": "a"}})
+
+	want := Store{
+		"kind-dev":   {"env": "staging"},
+		"kind-local": {"team": "a"},
+	}
+	if !cmp.Equal(want, store) {
+		diff := cmp.Diff(want, store)
+		t.Errorf("store.Apply() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_ReadWrite(t *testing.T) {
+	currentConfig := &config.Config{
+		Label: config.Label{
+			File: filepath.Join(t.TempDir(), "kontext", "labels.json"),
+		},
+	}
+
+	got, err := Read(currentConfig)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("want an empty store, got: '%v'", got)
+	}
+
+	want := Store{
+		"kind-dev": {"env": "dev"},
+	}
+	err = Write(currentConfig, want)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	got, err = Read(currentConfig)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if !cmp.Equal(want, got) {
+		diff := cmp.Diff(want, got)
+		t.Errorf("label.Read() mismatch (-want +got):\n%s", diff)
+	}
+}
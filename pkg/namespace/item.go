@@ -0,0 +1,23 @@
+package namespace
+
+// Item is the machine-readable representation of a namespace, its fields are part of the public output schema
+type Item struct {
+	Name    string `json:"name"`
+	Active  bool   `json:"active"`
+	Context string `json:"context"`
+}
+
+// BuildItems converts the given namespaces into items, while keeping their order
+func (c *Client) BuildItems(active string, namespaces ...string) []Item {
+	buffer := []Item{}
+
+	for _, namespace := range namespaces {
+		buffer = append(buffer, Item{
+			Name:    namespace,
+			Active:  namespace == active,
+			Context: c.APIConfig.CurrentContext,
+		})
+	}
+
+	return buffer
+}
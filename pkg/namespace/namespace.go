@@ -0,0 +1,176 @@
+package namespace
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+type Client struct {
+	Config    *config.Config
+	State     *state.State
+	APIConfig *api.Config
+}
+
+const (
+	MaxSelectHeight        = 500
+	PreviousNamespaceAlias = "-"
+	// DefaultNamespace is used by kubernetes clients, if the context does not define a namespace
+	DefaultNamespace = "default"
+)
+
+func New(configFile string) (*Client, error) {
+	configClient := &config.Client{
+		File: configFile,
+	}
+	config, err := configClient.Read()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(config.Global.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := state.Read(config)
+	if err != nil {
+		return nil, err
+	}
+
+	apiConfig, err := kubeconfig.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		Config:    config,
+		State:     state,
+		APIConfig: apiConfig,
+	}, nil
+}
+
+// Get returns the namespace of the active context, it falls back to the default namespace
+func (c *Client) Get() (string, error) {
+	log := logger.New()
+	log.Debug("getting namespace", log.Args("context", c.APIConfig.CurrentContext))
+
+	context, err := c.activeContext()
+	if err != nil {
+		return "", err
+	}
+
+	if len(context.Namespace) == 0 {
+		return DefaultNamespace, nil
+	}
+	return context.Namespace, nil
+}
+
+// List returns all namespaces, that are configured for the active context
+// The namespace of the active context is always part of the result.
+func (c *Client) List() ([]string, error) {
+	log := logger.New()
+	log.Debug("listing namespaces", log.Args("context", c.APIConfig.CurrentContext))
+
+	active, err := c.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	buffer, err := c.configured()
+	if err != nil {
+		return nil, err
+	}
+
+	if !lo.Contains(buffer, active) {
+		buffer = append([]string{active}, buffer...)
+	}
+
+	return buffer, nil
+}
+
+// Set changes the namespace of the active context
+// The history is kept per context, so '-' switches to the previous namespace of the active context.
+func (c *Client) Set(namespaceName string) error {
+	log := logger.New()
+
+	context, err := c.activeContext()
+	if err != nil {
+		return err
+	}
+	contextName := c.APIConfig.CurrentContext
+	history := c.State.Namespace.History[contextName]
+	// the namespace of the context is the first entry, so '-' already works after the first switch
+	if len(history) == 0 {
+		history = state.ComputeHistory(c.Config, state.History(lo.Ternary(len(context.Namespace) > 0, context.Namespace, DefaultNamespace)), history)
+	}
+
+	if namespaceName == PreviousNamespaceAlias {
+		// unlike contexts, any name is a valid namespace, so the alias must not be applied literally
+		if len(history) < 2 {
+			return fmt.Errorf("there is no previous namespace for context '%s'", contextName)
+		}
+		namespaceName = string(history[len(history)-2])
+	}
+
+	if len(namespaceName) == 0 {
+		printer, err := c.buildInteractiveSelectPrinter()
+		if err != nil {
+			return err
+		}
+		namespaceName, err = printer.Show()
+		if err != nil {
+			return err
+		}
+	}
+
+	context.Namespace = namespaceName
+	if c.State.Namespace.Active == nil {
+		c.State.Namespace.Active = map[string]string{}
+	}
+	if c.State.Namespace.History == nil {
+		c.State.Namespace.History = map[string][]state.History{}
+	}
+	c.State.Namespace.Active[contextName] = namespaceName
+	c.State.Namespace.History[contextName] = state.ComputeHistory(c.Config, state.History(namespaceName), history)
+
+	log.Info("switched namespace", log.Args("context", contextName, "namespace", namespaceName))
+	return nil
+}
+
+// configured returns the namespaces of all context items, that match the active context
+func (c *Client) configured() ([]string, error) {
+	var buffer []string
+
+	for _, item := range c.Config.Context.Items {
+		match, err := doublestar.Match(item.Name, c.APIConfig.CurrentContext)
+		if err != nil {
+			return nil, fmt.Errorf("invalid context pattern '%s', err: '%w'", item.Name, err)
+		}
+		if match {
+			buffer = append(buffer, item.Namespaces...)
+		}
+	}
+
+	return lo.Uniq(buffer), nil
+}
+
+// activeContext returns the current context of the api config
+func (c *Client) activeContext() (*api.Context, error) {
+	if len(c.APIConfig.CurrentContext) == 0 {
+		return nil, fmt.Errorf("there is no active context")
+	}
+
+	context, ok := c.APIConfig.Contexts[c.APIConfig.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("could not find context: '%s'", c.APIConfig.CurrentContext)
+	}
+
+	return context, nil
+}
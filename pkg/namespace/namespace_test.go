@@ -0,0 +1,391 @@
+package namespace
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/context"
+	"github.com/orbatschow/kontext/pkg/state"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_Get(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiConfig *api.Config
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "should return the namespace of the active context",
+			apiConfig: &api.Config{
+				CurrentContext: "kind",
+				Contexts: map[string]*api.Context{
+					"kind": {Namespace: "kube-system"},
+				},
+			},
+			want: "kube-system",
+		},
+		{
+			name: "should return the default namespace, as the active context does not define one",
+			apiConfig: &api.Config{
+				CurrentContext: "kind",
+				Contexts: map[string]*api.Context{
+					"kind": {},
+				},
+			},
+			want: DefaultNamespace,
+		},
+		{
+			name: "should throw an error, as there is no active context",
+			apiConfig: &api.Config{
+				Contexts: map[string]*api.Context{
+					"kind": {},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as the active context does not exist",
+			apiConfig: &api.Config{
+				CurrentContext: "local",
+				Contexts: map[string]*api.Context{
+					"kind": {},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				APIConfig: tt.apiConfig,
+			}
+
+			got, err := client.Get()
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.want != got {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_List(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *config.Config
+		apiConfig *api.Config
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name: "should return the namespaces of all matching context items and the active namespace",
+			config: &config.Config{
+				Context: config.Contexts{
+					Items: []config.ContextItem{
+						{Name: "kind-*", Namespaces: []string{"default", "kube-system"}},
+						{Name: "kind-dev", Namespaces: []string{"kube-system", "monitoring"}},
+						{Name: "prod-*", Namespaces: []string{"payment"}},
+					},
+				},
+			},
+			apiConfig: &api.Config{
+				CurrentContext: "kind-dev",
+				Contexts: map[string]*api.Context{
+					"kind-dev": {Namespace: "ingress"},
+				},
+			},
+			want: []string{"ingress", "default", "kube-system", "monitoring"},
+		},
+		{
+			name:   "should return the active namespace, as there are no namespaces configured",
+			config: &config.Config{},
+			apiConfig: &api.Config{
+				CurrentContext: "kind-dev",
+				Contexts: map[string]*api.Context{
+					"kind-dev": {},
+				},
+			},
+			want: []string{DefaultNamespace},
+		},
+		{
+			name: "should throw an error, as the context pattern is invalid",
+			config: &config.Config{
+				Context: config.Contexts{
+					Items: []config.ContextItem{
+						{Name: "kind-[", Namespaces: []string{"default"}},
+					},
+				},
+			},
+			apiConfig: &api.Config{
+				CurrentContext: "kind-dev",
+				Contexts: map[string]*api.Context{
+					"kind-dev": {},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config:    tt.config,
+				APIConfig: tt.apiConfig,
+			}
+
+			got, err := client.List()
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("namespace.List() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Set(t *testing.T) {
+	type args struct {
+		NamespaceName string
+		State         *state.State
+		APIConfig     *api.Config
+	}
+	tests := []struct {
+		name string
+		args args
+		want *struct {
+			state     *state.State
+			apiConfig *api.Config
+		}
+		wantErr bool
+	}{
+		{
+			name: "should change the namespace of the active context",
+			args: args{
+				NamespaceName: "kube-system",
+				State:         &state.State{},
+				APIConfig: &api.Config{
+					CurrentContext: "kind",
+					Contexts: map[string]*api.Context{
+						"kind":  {},
+						"local": {},
+					},
+				},
+			},
+			want: &struct {
+				state     *state.State
+				apiConfig *api.Config
+			}{
+				state: &state.State{
+					Namespace: state.Namespace{
+						Active:  map[string]string{"kind": "kube-system"},
+						History: map[string][]state.History{"kind": {"default", "kube-system"}},
+					},
+				},
+				apiConfig: &api.Config{
+					CurrentContext: "kind",
+					Contexts: map[string]*api.Context{
+						"kind":  {Namespace: "kube-system"},
+						"local": {},
+					},
+				},
+			},
+		},
+		{
+			name: "should change the namespace to the previous namespace",
+			args: args{
+				NamespaceName: PreviousNamespaceAlias,
+				State: &state.State{
+					Namespace: state.Namespace{
+						Active:  map[string]string{"kind": "kube-system", "local": "monitoring"},
+						History: map[string][]state.History{"kind": {"default", "kube-system"}, "local": {"monitoring"}},
+					},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: "kind",
+					Contexts: map[string]*api.Context{
+						"kind": {Namespace: "kube-system"},
+					},
+				},
+			},
+			want: &struct {
+				state     *state.State
+				apiConfig *api.Config
+			}{
+				state: &state.State{
+					Namespace: state.Namespace{
+						Active:  map[string]string{"kind": "default", "local": "monitoring"},
+						History: map[string][]state.History{"kind": {"default", "kube-system", "default"}, "local": {"monitoring"}},
+					},
+				},
+				apiConfig: &api.Config{
+					CurrentContext: "kind",
+					Contexts: map[string]*api.Context{
+						"kind": {Namespace: "default"},
+					},
+				},
+			},
+		},
+		{
+			name: "should throw an error, as there is no previous namespace for the active context",
+			args: args{
+				NamespaceName: PreviousNamespaceAlias,
+				State: &state.State{
+					Namespace: state.Namespace{
+						Active:  map[string]string{"kind": "kube-system", "local": "default"},
+						History: map[string][]state.History{"kind": {"kube-system"}, "local": {"monitoring", "default"}},
+					},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: "kind",
+					Contexts: map[string]*api.Context{
+						"kind": {Namespace: "kube-system"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as there is no active context",
+			args: args{
+				NamespaceName: "kube-system",
+				State:         &state.State{},
+				APIConfig: &api.Config{
+					Contexts: map[string]*api.Context{
+						"kind": {},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config: &config.Config{
+					State: config.State{
+						History: config.History{
+							Size: state.DefaultMaximumHistorySize,
+						},
+					},
+				},
+				State:     tt.args.State,
+				APIConfig: tt.args.APIConfig,
+			}
+
+			err := client.Set(tt.args.NamespaceName)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want.state, client.State) {
+				diff := cmp.Diff(tt.want.state, client.State)
+				t.Errorf("namespace.Set() state mismatch (-want +got):\n%s", diff)
+			}
+
+			if !tt.wantErr && !cmp.Equal(tt.want.apiConfig, client.APIConfig) {
+				diff := cmp.Diff(tt.want.apiConfig, client.APIConfig)
+				t.Errorf("namespace.Set() api config mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Set_ContextSwitch(t *testing.T) {
+	kontextConfig := &config.Config{
+		State: config.State{
+			History: config.History{
+				Size: state.DefaultMaximumHistorySize,
+			},
+		},
+	}
+	currentState := &state.State{}
+	apiConfig := &api.Config{
+		CurrentContext: "kind",
+		Contexts: map[string]*api.Context{
+			"kind":  {},
+			"local": {},
+		},
+	}
+	namespaceClient := Client{
+		Config:    kontextConfig,
+		State:     currentState,
+		APIConfig: apiConfig,
+	}
+	contextClient := context.Client{
+		Config:    kontextConfig,
+		State:     currentState,
+		APIConfig: apiConfig,
+	}
+
+	steps := []struct {
+		context   string
+		namespace string
+		want      string
+		wantErr   bool
+	}{
+		{context: "kind", namespace: "kube-system", want: "kube-system"},
+		// the initial namespace of the context is part of the history
+		{context: "kind", namespace: PreviousNamespaceAlias, want: "default"},
+		{context: "kind", namespace: "kube-system", want: "kube-system"},
+		{context: "kind", namespace: "monitoring", want: "monitoring"},
+		{context: "local", namespace: "default", want: "default"},
+		// the previous namespace of kind must not leak into local
+		{context: "local", namespace: PreviousNamespaceAlias, want: "default", wantErr: true},
+		{context: "kind", namespace: PreviousNamespaceAlias, want: "kube-system"},
+	}
+	for _, step := range steps {
+		err := contextClient.Set(step.context)
+		if err != nil {
+			t.Fatalf("unexpected error, err: '%v'", err)
+		}
+
+		err = namespaceClient.Set(step.namespace)
+		if !step.wantErr && err != nil {
+			t.Errorf("unexpected error, err: '%v'", err)
+		}
+
+		if step.wantErr && err == nil {
+			t.Errorf("expected error, got: '%v'", err)
+		}
+
+		got, err := namespaceClient.Get()
+		if err != nil {
+			t.Fatalf("unexpected error, err: '%v'", err)
+		}
+		if step.want != got {
+			t.Errorf("want: '%s', got: '%s'", step.want, got)
+		}
+		if step.want != currentState.Namespace.Active[step.context] {
+			t.Errorf("want: '%s', got: '%s'", step.want, currentState.Namespace.Active[step.context])
+		}
+	}
+
+	want := map[string][]state.History{
+		"kind":  {"default", "kube-system", "default", "kube-system", "monitoring", "kube-system"},
+		"local": {"default"},
+	}
+	if !cmp.Equal(want, currentState.Namespace.History) {
+		diff := cmp.Diff(want, currentState.Namespace.History)
+		t.Errorf("namespace.Set() history mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,22 @@
+package namespace
+
+import (
+	"github.com/pterm/pterm"
+)
+
+func (c *Client) BuildTablePrinter(active string, namespaces ...string) *pterm.TablePrinter {
+	table := pterm.TableData{
+		{"Active", "Name"},
+	}
+
+	for _, namespace := range namespaces {
+		marker := ""
+		if namespace == active {
+			marker = "*"
+		}
+		table = append(table, []string{
+			marker, namespace,
+		})
+	}
+	return pterm.DefaultTable.WithHasHeader().WithData(table)
+}
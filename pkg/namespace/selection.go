@@ -0,0 +1,37 @@
+package namespace
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pterm/pterm"
+	"github.com/samber/lo"
+)
+
+// start an interactive namespace selection
+func (c *Client) buildInteractiveSelectPrinter() (*pterm.InteractiveSelectPrinter, error) {
+	active, err := c.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	// compute all selection options
+	keys, err := c.configured()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("there are no namespaces configured for context: '%s'", c.APIConfig.CurrentContext)
+	}
+	sort.Strings(keys)
+
+	// only preselect the active namespace, if it is part of the options
+	selector := pterm.DefaultInteractiveSelect.
+		WithMaxHeight(MaxSelectHeight).
+		WithOptions(keys)
+	if !lo.Contains(keys, active) {
+		return selector, nil
+	}
+
+	return selector.WithDefaultOption(active), nil
+}
@@ -0,0 +1,146 @@
+package namespace
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/pterm/pterm"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_buildInteractiveSelectPrinter(t *testing.T) {
+	type args struct {
+		Config    *config.Config
+		APIConfig *api.Config
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *pterm.InteractiveSelectPrinter
+		wantErr bool
+	}{
+		{
+			name: "should return a printer, that sorts the configured namespaces and selects the active one",
+			args: args{
+				Config: &config.Config{
+					Context: config.Contexts{
+						Items: []config.ContextItem{
+							{Name: "kind-*", Namespaces: []string{"kube-system", "default", "monitoring"}},
+						},
+					},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: "kind-dev",
+					Contexts: map[string]*api.Context{
+						"kind-dev": {Namespace: "kube-system"},
+					},
+				},
+			},
+			want: &pterm.InteractiveSelectPrinter{
+				TextStyle: &pterm.Style{
+					pterm.FgLightCyan,
+				},
+				DefaultText: "Please select an option",
+				Options: []string{
+					"default",
+					"kube-system",
+					"monitoring",
+				},
+				OptionStyle: &pterm.Style{
+					pterm.FgDefault,
+					pterm.BgDefault,
+				},
+				DefaultOption: "kube-system",
+				MaxHeight:     MaxSelectHeight,
+				Selector:      ">",
+				SelectorStyle: &pterm.Style{
+					pterm.FgLightMagenta,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should return a printer without default, as the active namespace is not configured",
+			args: args{
+				Config: &config.Config{
+					Context: config.Contexts{
+						Items: []config.ContextItem{
+							{Name: "kind-*", Namespaces: []string{"monitoring"}},
+						},
+					},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: "kind-dev",
+					Contexts: map[string]*api.Context{
+						"kind-dev": {},
+					},
+				},
+			},
+			want: &pterm.InteractiveSelectPrinter{
+				TextStyle: &pterm.Style{
+					pterm.FgLightCyan,
+				},
+				DefaultText: "Please select an option",
+				Options: []string{
+					"monitoring",
+				},
+				OptionStyle: &pterm.Style{
+					pterm.FgDefault,
+					pterm.BgDefault,
+				},
+				DefaultOption: "",
+				MaxHeight:     MaxSelectHeight,
+				Selector:      ">",
+				SelectorStyle: &pterm.Style{
+					pterm.FgLightMagenta,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "should throw an error, as there are no namespaces configured for the active context",
+			args: args{
+				Config: &config.Config{
+					Context: config.Contexts{
+						Items: []config.ContextItem{
+							{Name: "prod-*", Namespaces: []string{"payment"}},
+						},
+					},
+				},
+				APIConfig: &api.Config{
+					CurrentContext: "kind-dev",
+					Contexts: map[string]*api.Context{
+						"kind-dev": {},
+					},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := Client{
+				Config:    tt.args.Config,
+				APIConfig: tt.args.APIConfig,
+			}
+
+			got, err := client.buildInteractiveSelectPrinter()
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			options := cmpopts.IgnoreUnexported(pterm.InteractiveSelectPrinter{})
+			if !cmp.Equal(&tt.want, &got, options) {
+				diff := cmp.Diff(tt.want, got, options)
+				t.Errorf("namespace.buildInteractiveSelectPrinter() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatName  Format = "name"
+	FormatWide  Format = "wide"
+)
+
+// Formats contains all supported output formats
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatName, FormatWide}
+
+// AddFlag registers the output flag for the given command
+func AddFlag(cmd *cobra.Command, target *string) {
+	cmd.Flags().StringVarP(target, "output", "o", string(FormatTable),
+		fmt.Sprintf("output format, one of: %s", strings.Join(lo.Map(Formats, func(item Format, _ int) string {
+			return string(item)
+		}), "|")))
+}
+
+// Parse validates the given output format
+func Parse(value string) (Format, error) {
+	format := Format(value)
+	if !lo.Contains(Formats, format) {
+		return "", fmt.Errorf("unsupported output format: '%s'", value)
+	}
+	return format, nil
+}
+
+// Write serializes the given items in a machine-readable format
+func Write(writer io.Writer, format Format, items any) error {
+	var buffer []byte
+	var err error
+
+	switch format {
+	case FormatJSON:
+		buffer, err = json.MarshalIndent(items, "", "  ")
+		buffer = append(buffer, '\n')
+	case FormatYAML:
+		buffer, err = yaml.Marshal(items)
+	default:
+		return fmt.Errorf("output format '%s' is not machine-readable", format)
+	}
+	if err != nil {
+		return fmt.Errorf("could not serialize output, err: '%w'", err)
+	}
+
+	_, err = writer.Write(buffer)
+	return err
+}
+
+// WriteNames writes one name per line
+func WriteNames(writer io.Writer, names ...string) error {
+	for _, name := range names {
+		_, err := fmt.Fprintln(writer, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
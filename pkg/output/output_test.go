@@ -0,0 +1,91 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+type item struct {
+	Name   string   `json:"name"`
+	Active bool     `json:"active"`
+	Files  []string `json:"files"`
+}
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Format
+		wantErr bool
+	}{
+		{value: "table", want: FormatTable},
+		{value: "json", want: FormatJSON},
+		{value: "yaml", want: FormatYAML},
+		{value: "name", want: FormatName},
+		{value: "wide", want: FormatWide},
+		{value: "xml", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.want != got {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Write(t *testing.T) {
+	items := []item{
+		{Name: "kind", Active: true, Files: []string{"a.yaml"}},
+	}
+
+	tests := []struct {
+		name    string
+		format  Format
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "should serialize the items as json",
+			format: FormatJSON,
+			want:   "[\n  {\n    \"name\": \"kind\",\n    \"active\": true,\n    \"files\": [\n      \"a.yaml\"\n    ]\n  }\n]\n",
+		},
+		{
+			name:   "should serialize the items as yaml",
+			format: FormatYAML,
+			want:   "- active: true\n  files:\n  - a.yaml\n  name: kind\n",
+		},
+		{
+			name:    "should throw an error, as tables are not machine-readable",
+			format:  FormatTable,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+
+			err := Write(&buffer, tt.format, items)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.want != buffer.String() {
+				t.Errorf("want: '%s', got: '%s'", tt.want, buffer.String())
+			}
+		})
+	}
+}
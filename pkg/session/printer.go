@@ -0,0 +1,30 @@
+package session
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+func (c *Client) BuildTablePrinter(active string, sessions ...Session) *pterm.TablePrinter {
+	table := pterm.TableData{
+		{"Active", "ID", "PID", "Created", "Stale"},
+	}
+
+	for _, session := range sessions {
+		marker := ""
+		if session.ID == active {
+			marker = "*"
+		}
+		stale := ""
+		if !session.Alive() {
+			stale = "*"
+		}
+		table = append(table, []string{
+			marker, session.ID, strconv.Itoa(session.PID), session.Created.Format(time.DateTime), stale,
+		})
+	}
+
+	return pterm.DefaultTable.WithHasHeader().WithData(table)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package session
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code of processes, that are still running
+const stillActive = 259
+
+func alive(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle)
+
+	var code uint32
+	err = windows.GetExitCodeProcess(handle, &code)
+	if err != nil {
+		return false
+	}
+	return code == stillActive
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/orbatschow/kontext/pkg/config"
+)
+
+const (
+	ShellPosix      = "posix"
+	ShellFish       = "fish"
+	ShellPowerShell = "powershell"
+)
+
+// DetectShell derives the shell dialect from the path of a shell binary, it defaults to posix
+func DetectShell(path string) string {
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(path)), ".exe")
+
+	switch name {
+	case "fish":
+		return ShellFish
+	case "pwsh", "powershell":
+		return ShellPowerShell
+	default:
+		return ShellPosix
+	}
+}
+
+// Script builds the shell code, that activates the given session when evaluated
+// The shell records its own pid, as the parent of kontext might be a short-lived subshell.
+func (c *Client) Script(session *Session, shell string) (string, error) {
+	kubeconfig := c.Kubeconfig(session.ID)
+	pidFile := filepath.Join(c.Directory(session.ID), PIDFile)
+
+	switch shell {
+	case ShellPosix:
+		return fmt.Sprintf("export KUBECONFIG=%s\nexport %s=%s\necho $$ > %s\n",
+			quotePosix(kubeconfig), config.SessionEnvironmentVariable, quotePosix(session.ID), quotePosix(pidFile)), nil
+	case ShellFish:
+		return fmt.Sprintf("set -gx KUBECONFIG %s\nset -gx %s %s\necho $fish_pid > %s\n",
+			quoteFish(kubeconfig), config.SessionEnvironmentVariable, quoteFish(session.ID), quoteFish(pidFile)), nil
+	case ShellPowerShell:
+		return fmt.Sprintf("$Env:KUBECONFIG = %s\n$Env:%s = %s\nSet-Content -NoNewline -Path %s -Value $PID\n",
+			quotePowerShell(kubeconfig), config.SessionEnvironmentVariable, quotePowerShell(session.ID), quotePowerShell(pidFile)), nil
+	default:
+		return "", fmt.Errorf("unsupported shell: '%s'", shell)
+	}
+}
+
+// quotePosix wraps the value in single quotes, embedded single quotes are closed, escaped and reopened
+func quotePosix(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quoteFish wraps the value in single quotes, fish allows escaping within single quotes
+func quoteFish(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// quotePowerShell wraps the value in single quotes, embedded single quotes are doubled
+func quotePowerShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// Shell returns the path of the users shell, it falls back to the default shell of the operating system
+func Shell() string {
+	if shell := os.Getenv("SHELL"); len(shell) > 0 {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		if shell := os.Getenv("COMSPEC"); len(shell) > 0 {
+			return shell
+		}
+		return "cmd.exe"
+	}
+	return "/bin/sh"
+}
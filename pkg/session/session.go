@@ -0,0 +1,228 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/orbatschow/kontext/pkg/utils/file"
+	"github.com/samber/lo"
+)
+
+const (
+	// MetadataFile describes the session within its directory
+	MetadataFile = "session.json"
+	// PIDFile holds the process, that owns the session, it is rewritten by the shell, that activates the session
+	PIDFile  = "pid"
+	idLength = 8
+)
+
+// Session is an isolated copy of the kubeconfig and state, that belongs to a single shell
+type Session struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	// PID of the process, that owns the session, the session is stale as soon as the process exited
+	PID int `json:"-"`
+}
+
+type Client struct {
+	Config *config.Config
+}
+
+func New(configFile string) (*Client, error) {
+	configClient := &config.Client{
+		File: configFile,
+	}
+	config, err := configClient.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		Config: config,
+	}, nil
+}
+
+// Create creates a new session, that is owned by the given process
+// The kubeconfig and state of the current scope are copied into the session directory.
+func (c *Client) Create(pid int) (*Session, error) {
+	log := logger.New()
+
+	session := &Session{
+		ID:      strings.ToLower(lo.RandomString(idLength, lo.AlphanumericCharset)),
+		PID:     pid,
+		Created: time.Now(),
+	}
+	directory := c.Directory(session.ID)
+
+	kubeconfig, err := os.ReadFile(c.Config.Global.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not read kubeconfig, err: '%w'", err)
+	}
+
+	err = os.MkdirAll(directory, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create session directory, err: '%w'", err)
+	}
+
+	err = c.populate(session, directory, kubeconfig)
+	if err != nil {
+		// do not leave a partial session behind
+		_ = os.RemoveAll(directory)
+		return nil, err
+	}
+
+	log.Debug("created session", log.Args("session", session.ID, "pid", pid, "directory", directory))
+	return session, nil
+}
+
+// populate writes the kubeconfig, state and metadata of the session into its directory
+func (c *Client) populate(session *Session, directory string, kubeconfig []byte) error {
+	err := file.WriteAtomic(filepath.Join(directory, config.SessionKubeconfigFile), kubeconfig, 0600)
+	if err != nil {
+		return err
+	}
+
+	// copy the state, backup revisions are not part of the session, as they are shared with the global scope
+	currentState := &state.State{}
+	if _, err := os.Stat(c.Config.State.File); err == nil {
+		currentState, err = state.Read(c.Config)
+		if err != nil {
+			return err
+		}
+	}
+	currentState.Backup = state.Backup{}
+
+	sessionConfig := *c.Config
+	sessionConfig.State.File = filepath.Join(directory, config.SessionStateFile)
+	err = state.Write(&sessionConfig, currentState)
+	if err != nil {
+		return err
+	}
+
+	// write the session metadata
+	buffer, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	err = file.WriteAtomic(filepath.Join(directory, MetadataFile), buffer, 0600)
+	if err != nil {
+		return err
+	}
+
+	return file.WriteAtomic(filepath.Join(directory, PIDFile), []byte(strconv.Itoa(session.PID)), 0600)
+}
+
+// List returns all sessions within the session directory
+func (c *Client) List() ([]Session, error) {
+	log := logger.New()
+	var buffer []Session
+
+	entries, err := os.ReadDir(c.Config.Session.Directory)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read session directory, err: '%w'", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(c.Directory(entry.Name()), MetadataFile))
+		if err != nil {
+			log.Warn("skipping session, it has no metadata", log.Args("session", entry.Name()))
+			continue
+		}
+
+		var session Session
+		err = json.Unmarshal(data, &session)
+		if err != nil {
+			log.Warn("skipping session, its metadata is invalid", log.Args("session", entry.Name()))
+			continue
+		}
+
+		// a missing or invalid pid file leaves the pid at zero, which marks the session as stale
+		data, err = os.ReadFile(filepath.Join(c.Directory(entry.Name()), PIDFile))
+		if err == nil {
+			session.PID, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		}
+		buffer = append(buffer, session)
+	}
+
+	return buffer, nil
+}
+
+// Delete removes the session directory with all its files
+func (c *Client) Delete(id string) error {
+	log := logger.New()
+
+	if len(id) == 0 {
+		return fmt.Errorf("given session id is empty")
+	}
+	err := config.ValidateSession(id)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(c.Directory(id))
+	if err != nil {
+		return fmt.Errorf("could not remove session '%s', err: '%w'", id, err)
+	}
+	log.Debug("removed session", log.Args("session", id))
+
+	return nil
+}
+
+// Cleanup removes all sessions, whose owning process does not exist anymore
+func (c *Client) Cleanup() ([]Session, error) {
+	log := logger.New()
+	var buffer []Session
+
+	sessions, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.Alive() {
+			continue
+		}
+		err := c.Delete(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("removed stale session", log.Args("session", session.ID, "pid", session.PID))
+		buffer = append(buffer, session)
+	}
+
+	return buffer, nil
+}
+
+// Directory computes the directory of the session with the given id
+func (c *Client) Directory(id string) string {
+	return filepath.Join(c.Config.Session.Directory, id)
+}
+
+// Kubeconfig computes the kubeconfig file of the session with the given id
+func (c *Client) Kubeconfig(id string) string {
+	return filepath.Join(c.Directory(id), config.SessionKubeconfigFile)
+}
+
+// Alive checks if the process, that owns the session, is still running
+func (s *Session) Alive() bool {
+	if s.PID <= 0 {
+		return false
+	}
+	return alive(s.PID)
+}
@@ -0,0 +1,220 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/samber/lo"
+)
+
+func newTestClient(t *testing.T) *Client {
+	_, caller, _, _ := runtime.Caller(0)
+	directory := t.TempDir()
+
+	return &Client{
+		Config: &config.Config{
+			Global: config.Global{
+				Kubeconfig: filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml"),
+			},
+			State: config.State{
+				File: filepath.Join(directory, "state.json"),
+			},
+			Session: config.Session{
+				Directory: filepath.Join(directory, "sessions"),
+			},
+		},
+	}
+}
+
+func Test_Create(t *testing.T) {
+	client := newTestClient(t)
+
+	err := state.Write(client.Config, &state.State{
+		Context: state.Context{
+			Active: "kind-dev",
+		},
+		Backup: state.Backup{
+			Revisions: []state.Revision{"kubeconfig-1.yaml"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	session, err := client.Create(os.Getpid())
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	want, err := os.ReadFile(client.Config.Global.Kubeconfig)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	got, err := os.ReadFile(client.Kubeconfig(session.ID))
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("session kubeconfig does not match the current kubeconfig")
+	}
+
+	sessionConfig := *client.Config
+	sessionConfig.State.File = filepath.Join(client.Directory(session.ID), config.SessionStateFile)
+	sessionState, err := state.Read(&sessionConfig)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	wantState := &state.State{
+		Context: state.Context{
+			Active: "kind-dev",
+		},
+	}
+	if !cmp.Equal(wantState, sessionState) {
+		diff := cmp.Diff(wantState, sessionState)
+		t.Errorf("session.Create() state mismatch (-want +got):\n%s", diff)
+	}
+
+	sessions, err := client.List()
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != session.ID || sessions[0].PID != os.Getpid() {
+		t.Errorf("want session '%s', got: '%v'", session.ID, sessions)
+	}
+}
+
+func Test_Cleanup(t *testing.T) {
+	client := newTestClient(t)
+
+	alive, err := client.Create(os.Getpid())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// pid 0 never belongs to a user process, so the session is always stale
+	stale, err := client.Create(0)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	removed, err := client.Cleanup()
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	if len(removed) != 1 || removed[0].ID != stale.ID {
+		t.Errorf("want removed session '%s', got: '%v'", stale.ID, removed)
+	}
+
+	if _, err := os.Stat(client.Directory(stale.ID)); !os.IsNotExist(err) {
+		t.Errorf("want stale session directory to be removed, err: '%v'", err)
+	}
+
+	sessions, err := client.List()
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	got := lo.Map(sessions, func(item Session, _ int) string {
+		return item.ID
+	})
+	if !cmp.Equal([]string{alive.ID}, got) {
+		diff := cmp.Diff([]string{alive.ID}, got)
+		t.Errorf("session.Cleanup() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_Script(t *testing.T) {
+	client := &Client{
+		Config: &config.Config{
+			Session: config.Session{
+				Directory: "/run/user/1000/kontext/sessions",
+			},
+		},
+	}
+	session := &Session{
+		ID: "abc",
+	}
+
+	tests := []struct {
+		name    string
+		shell   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "should export the session for posix shells",
+			shell: ShellPosix,
+			want:  "export KUBECONFIG='/run/user/1000/kontext/sessions/abc/kubeconfig.yaml'\nexport KONTEXT_SESSION='abc'\necho $$ > '/run/user/1000/kontext/sessions/abc/pid'\n",
+		},
+		{
+			name:  "should export the session for fish",
+			shell: ShellFish,
+			want:  "set -gx KUBECONFIG '/run/user/1000/kontext/sessions/abc/kubeconfig.yaml'\nset -gx KONTEXT_SESSION 'abc'\necho $fish_pid > '/run/user/1000/kontext/sessions/abc/pid'\n",
+		},
+		{
+			name:  "should export the session for powershell",
+			shell: ShellPowerShell,
+			want:  "$Env:KUBECONFIG = '/run/user/1000/kontext/sessions/abc/kubeconfig.yaml'\n$Env:KONTEXT_SESSION = 'abc'\nSet-Content -NoNewline -Path '/run/user/1000/kontext/sessions/abc/pid' -Value $PID\n",
+		},
+		{
+			name:    "should throw an error, as the shell is not supported",
+			shell:   "tcsh",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.Script(session, tt.shell)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !tt.wantErr && tt.want != got {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_DetectShell(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/bin/bash", want: ShellPosix},
+		{path: "/usr/bin/zsh", want: ShellPosix},
+		{path: "/usr/local/bin/fish", want: ShellFish},
+		{path: "/usr/bin/pwsh", want: ShellPowerShell},
+		{path: "powershell.exe", want: ShellPowerShell},
+		{path: "", want: ShellPosix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := DetectShell(tt.path)
+			if tt.want != got {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	client := newTestClient(t)
+
+	// the state directory must not be removed by an id, that leaves the session directory
+	directory := filepath.Dir(client.Config.State.File)
+	err := client.Delete(filepath.Join("..", filepath.Base(directory)))
+	if err == nil {
+		t.Errorf("expected error, got: '%v'", err)
+	}
+	if _, err := os.Stat(directory); err != nil {
+		t.Errorf("want directory '%s' to exist, err: '%v'", directory, err)
+	}
+}
@@ -0,0 +1,135 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/file"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ExecCacheDirectory is the directory within the cache directory, that contains the output of all exec sources
+const ExecCacheDirectory = "exec"
+
+// ExecMode decides, whether the commands of exec sources run, or only their cached kubeconfigs are read
+type ExecMode int
+
+const (
+	// ExecCached reads the kubeconfigs, that have been generated before, exec sources without one are skipped
+	// Listing and inspecting contexts must not run external commands, e.g. cloud provider clis, each time.
+	ExecCached ExecMode = iota
+	// ExecRun runs the commands, if their cached kubeconfigs are older than the ttl
+	ExecRun
+)
+
+// ComputeExec returns the kubeconfig, that is generated by the exec command of the given source
+// The output is cached, the command only runs, if the cached kubeconfig is older than the ttl of the source.
+// If the command fails, the cached kubeconfig is used regardless of its age.
+func ComputeExec(currentConfig *config.Config, source *config.SourceItem) (*os.File, error) {
+	log := logger.New()
+
+	path, err := execCachePath(currentConfig, source)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err == nil && source.Exec.TTL > 0 && time.Since(info.ModTime()) < source.Exec.TTL {
+		log.Debug("using cached exec kubeconfig", log.Args("source", source.Name, "file", path))
+		return os.Open(path)
+	}
+
+	data, err := runExec(source)
+	if err != nil && info != nil {
+		// a stale kubeconfig is better than none, e.g. if the cloud provider cli is temporarily unavailable
+		log.Warn("using stale exec kubeconfig", log.Args("source", source.Name, "file", path, "error", err.Error()))
+		return os.Open(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create exec cache directory, err: '%w'", err)
+	}
+	err = file.WriteAtomic(path, data, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not write exec kubeconfig, err: '%w'", err)
+	}
+	log.Debug("cached exec kubeconfig", log.Args("source", source.Name, "file", path))
+
+	return os.Open(path)
+}
+
+// cachedExec returns the cached kubeconfig of the given exec source regardless of its age, the command is not run
+func cachedExec(currentConfig *config.Config, source *config.SourceItem) (*os.File, error) {
+	path, err := execCachePath(currentConfig, source)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// runExec runs the command of the source and validates, that its stdout is a kubeconfig
+func runExec(source *config.SourceItem) ([]byte, error) {
+	log := logger.New()
+
+	ctx := context.Background()
+	if source.Exec.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, source.Exec.Timeout)
+		defer cancel()
+	}
+
+	var stdout bytes.Buffer
+	command := exec.CommandContext(ctx, source.Exec.Command, source.Exec.Args...)
+	command.Stdin = os.Stdin
+	command.Stdout = &stdout
+	// credential helpers might prompt for a login, so stderr belongs to the user
+	command.Stderr = os.Stderr
+	command.Env = os.Environ()
+	for _, item := range source.Exec.Env {
+		command.Env = append(command.Env, item.Name+"="+item.Value)
+	}
+
+	log.Debug("running exec source", log.Args("source", source.Name, "command", source.Exec.Command))
+	err := command.Run()
+	if err != nil {
+		return nil, fmt.Errorf("could not run exec command of source '%s', err: '%w'", source.Name, err)
+	}
+
+	_, err = clientcmd.Load(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("could not parse output of exec source '%s' as kubeconfig, err: '%w'", source.Name, err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// execCachePath computes the cache file of the source, changing the command, its args or env invalidates the cache
+func execCachePath(currentConfig *config.Config, source *config.SourceItem) (string, error) {
+	data, err := json.Marshal(struct {
+		Name    string
+		Command string
+		Args    []string
+		Env     []config.EnvVar
+	}{source.Name, source.Exec.Command, source.Exec.Args, source.Exec.Env})
+	if err != nil {
+		return "", fmt.Errorf("could not compute exec cache key, err: '%w'", err)
+	}
+
+	hash := sha256.Sum256(data)
+	return filepath.Join(currentConfig.Cache.Directory, ExecCacheDirectory, url.PathEscape(source.Name)+"-"+hex.EncodeToString(hash[:8])+".yaml"), nil
+}
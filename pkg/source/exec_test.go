@@ -0,0 +1,239 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+)
+
+func Test_ComputeExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the exec generator is a shell script")
+	}
+	_, caller, _, _ := runtime.Caller(0)
+	script := filepath.Join(caller, "..", "testdata", "06-exec-kubeconfig.sh")
+
+	type args struct {
+		exec config.Exec
+		// runs are executed in order and share the cache directory
+		runs int
+		// before modifies the arguments of the exec source, after the first run
+		before func(t *testing.T, exec *config.Exec)
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantContext string
+		wantRuns    int
+		wantErr     bool
+	}{
+		{
+			name: "should generate the kubeconfig and reuse it within the ttl",
+			args: args{
+				exec: config.Exec{Command: script, Args: []string{"kind-exec"}, TTL: time.Hour},
+				runs: 2,
+			},
+			wantContext: "kind-exec",
+			wantRuns:    1,
+		},
+		{
+			name: "should generate the kubeconfig each time, as there is no ttl",
+			args: args{
+				exec: config.Exec{Command: script, Args: []string{"kind-exec"}},
+				runs: 2,
+			},
+			wantContext: "kind-exec",
+			wantRuns:    2,
+		},
+		{
+			name: "should regenerate the kubeconfig, as the args have changed",
+			args: args{
+				exec: config.Exec{Command: script, Args: []string{"kind-exec"}, TTL: time.Hour},
+				runs: 2,
+				before: func(t *testing.T, exec *config.Exec) {
+					exec.Args = []string{"kind-changed"}
+				},
+			},
+			wantContext: "kind-changed",
+			wantRuns:    2,
+		},
+		{
+			name: "should use the stale kubeconfig, as the command fails",
+			args: args{
+				exec: config.Exec{Command: script, Args: []string{"kind-exec"}},
+				runs: 2,
+				before: func(t *testing.T, exec *config.Exec) {
+					// the environment of kontext is not part of the cache key
+					t.Setenv("KONTEXT_TEST_FAIL", "true")
+				},
+			},
+			wantContext: "kind-exec",
+			wantRuns:    2,
+		},
+		{
+			name: "should throw an error, as the command fails",
+			args: args{
+				exec: config.Exec{Command: script, Args: []string{"fail"}},
+				runs: 1,
+			},
+			wantRuns: 1,
+			wantErr:  true,
+		},
+		{
+			name: "should throw an error, as the output is not a kubeconfig",
+			args: args{
+				exec: config.Exec{Command: script, Args: []string{"invalid"}},
+				runs: 1,
+			},
+			wantRuns: 1,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := filepath.Join(t.TempDir(), "counter")
+			currentConfig := &config.Config{
+				Cache: config.Cache{Directory: t.TempDir()},
+			}
+			source := &config.SourceItem{
+				Name: "exec",
+				Exec: tt.args.exec,
+			}
+			source.Exec.Env = append(source.Exec.Env, config.EnvVar{Name: "KONTEXT_TEST_COUNTER", Value: counter})
+
+			var err error
+			var got string
+			for i := 0; i < tt.args.runs; i++ {
+				if i > 0 && tt.args.before != nil {
+					tt.args.before(t, &source.Exec)
+				}
+
+				var file *os.File
+				file, err = ComputeExec(currentConfig, source)
+				if err != nil {
+					break
+				}
+				apiConfig, readErr := kubeconfig.Read(file)
+				_ = file.Close()
+				if readErr != nil {
+					t.Fatalf("unexpected error, err: '%v'", readErr)
+				}
+				got = apiConfig.CurrentContext
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantContext != got {
+				t.Errorf("want: '%s', got: '%s'", tt.wantContext, got)
+			}
+
+			data, _ := os.ReadFile(counter)
+			if runs := strings.Count(string(data), "run"); tt.wantRuns != runs {
+				t.Errorf("want: '%d' runs, got: '%d'", tt.wantRuns, runs)
+			}
+		})
+	}
+}
+
+func Test_computeSource_Cached(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the exec generator is a shell script")
+	}
+	_, caller, _, _ := runtime.Caller(0)
+	script := filepath.Join(caller, "..", "testdata", "06-exec-kubeconfig.sh")
+
+	tests := []struct {
+		name string
+		mode ExecMode
+		// preview runs the source within a preview of fzf
+		preview bool
+		// cached runs the command once, before the source is read
+		cached    bool
+		wantFiles int
+		wantRuns  int
+	}{
+		{
+			name:      "should read the cached kubeconfig without running the command, although there is no ttl",
+			mode:      ExecCached,
+			cached:    true,
+			wantFiles: 1,
+			wantRuns:  1,
+		},
+		{
+			name:      "should skip the exec source, as there is no cached kubeconfig",
+			mode:      ExecCached,
+			wantFiles: 0,
+			wantRuns:  0,
+		},
+		{
+			name:      "should read the cached kubeconfig within a preview, although the command should run",
+			mode:      ExecRun,
+			preview:   true,
+			cached:    true,
+			wantFiles: 1,
+			wantRuns:  1,
+		},
+		{
+			name:      "should run the command again, as there is no ttl",
+			mode:      ExecRun,
+			cached:    true,
+			wantFiles: 1,
+			wantRuns:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := filepath.Join(t.TempDir(), "counter")
+			currentConfig := &config.Config{
+				Cache: config.Cache{Directory: t.TempDir()},
+			}
+			source := &config.SourceItem{
+				Name: "exec",
+				Exec: config.Exec{
+					Command: script,
+					Args:    []string{"kind-exec"},
+					Env:     []config.EnvVar{{Name: "KONTEXT_TEST_COUNTER", Value: counter}},
+				},
+			}
+
+			if tt.cached {
+				file, err := ComputeExec(currentConfig, source)
+				if err != nil {
+					t.Fatalf("%v", err)
+				}
+				_ = file.Close()
+			}
+
+			if tt.preview {
+				t.Setenv(config.PreviewEnvironmentVariable, "true")
+			}
+			got, err := computeSource(currentConfig, source, tt.mode)
+			if err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+			for _, file := range got {
+				_ = file.Close()
+			}
+
+			if tt.wantFiles != len(got) {
+				t.Errorf("want: '%d' files, got: '%d'", tt.wantFiles, len(got))
+			}
+			data, _ := os.ReadFile(counter)
+			if runs := strings.Count(string(data), "run"); tt.wantRuns != runs {
+				t.Errorf("want: '%d' runs, got: '%d'", tt.wantRuns, runs)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package source
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// filterContexts removes all contexts, that are not included or excluded by the context filter of the source,
+// clusters and users, that are no longer referred by a context, are removed as well
+func filterContexts(source *config.SourceItem, apiConfig *api.Config) (*api.Config, error) {
+	filter := source.Contexts
+	if len(filter.Include) == 0 && len(filter.Exclude) == 0 {
+		return apiConfig, nil
+	}
+
+	buffer := apiConfig.DeepCopy()
+	for name := range buffer.Contexts {
+		included := len(filter.Include) == 0
+		if !included {
+			match, err := matchContext(filter.Include, name)
+			if err != nil {
+				return nil, err
+			}
+			included = match
+		}
+		excluded, err := matchContext(filter.Exclude, name)
+		if err != nil {
+			return nil, err
+		}
+		if !included || excluded {
+			delete(buffer.Contexts, name)
+		}
+	}
+
+	return kubeconfig.Prune(buffer), nil
+}
+
+// matchContext reports, whether the name matches any of the given patterns, patterns with the regex prefix are
+// regular expressions, that have to match the whole name, all other patterns are glob patterns
+func matchContext(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		var match bool
+		var err error
+
+		if expression, ok := strings.CutPrefix(pattern, config.RegexPrefix); ok {
+			var compiled *regexp.Regexp
+			compiled, err = config.CompileRegex(expression)
+			if err == nil {
+				match = compiled.MatchString(name)
+			}
+		} else {
+			match, err = doublestar.Match(pattern, name)
+		}
+		if err != nil {
+			return false, fmt.Errorf("invalid context pattern '%s', err: '%w'", pattern, err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
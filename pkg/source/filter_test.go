@@ -0,0 +1,154 @@
+package source
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func Test_filterContexts(t *testing.T) {
+	apiConfig := &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"a": {Server: "https://a"},
+			"b": {Server: "https://b"},
+			"c": {Server: "https://c"},
+		},
+		AuthInfos: map[string]*api.AuthInfo{
+			"admin": {Token: "admin"},
+			"view":  {Token: "view"},
+		},
+		Contexts: map[string]*api.Context{
+			"a-prod": {Cluster: "a", AuthInfo: "admin"},
+			"a-dev":  {Cluster: "a", AuthInfo: "view"},
+			"b-prod": {Cluster: "b", AuthInfo: "admin"},
+			"c-dev":  {Cluster: "c", AuthInfo: "view"},
+		},
+		CurrentContext: "c-dev",
+	}
+
+	tests := []struct {
+		name          string
+		filter        config.ContextFilter
+		want          []string
+		wantClusters  []string
+		wantAuthInfos []string
+		wantCurrent   string
+		wantErr       bool
+	}{
+		{
+			name:          "should keep all contexts, as the filter is empty",
+			want:          []string{"a-dev", "a-prod", "b-prod", "c-dev"},
+			wantClusters:  []string{"a", "b", "c"},
+			wantAuthInfos: []string{"admin", "view"},
+			wantCurrent:   "c-dev",
+		},
+		{
+			name:          "should keep the included contexts and remove orphaned clusters and users",
+			filter:        config.ContextFilter{Include: []string{"*-prod"}},
+			want:          []string{"a-prod", "b-prod"},
+			wantClusters:  []string{"a", "b"},
+			wantAuthInfos: []string{"admin"},
+		},
+		{
+			name:          "should remove the excluded contexts of all included contexts",
+			filter:        config.ContextFilter{Include: []string{"a-*", "b-*"}, Exclude: []string{"b-*"}},
+			want:          []string{"a-dev", "a-prod"},
+			wantClusters:  []string{"a"},
+			wantAuthInfos: []string{"admin", "view"},
+		},
+		{
+			name:          "should match regular expressions",
+			filter:        config.ContextFilter{Exclude: []string{"regex:(a|b)-.*"}},
+			want:          []string{"c-dev"},
+			wantClusters:  []string{"c"},
+			wantAuthInfos: []string{"view"},
+			wantCurrent:   "c-dev",
+		},
+		{
+			name:          "should match regular expressions against the whole context name",
+			filter:        config.ContextFilter{Include: []string{"regex:prod", "regex:a-"}, Exclude: []string{"regex:dev"}},
+			want:          []string{},
+			wantClusters:  []string{},
+			wantAuthInfos: []string{},
+		},
+		{
+			name:    "should throw an error, as the glob pattern is invalid",
+			filter:  config.ContextFilter{Include: []string{"a-["}},
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the regular expression is invalid",
+			filter:  config.ContextFilter{Exclude: []string{"regex:a-("}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterContexts(&config.SourceItem{Name: "vendor", Contexts: tt.filter}, apiConfig)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			for _, item := range []struct {
+				want []string
+				got  []string
+			}{
+				{want: tt.want, got: lo.Keys(got.Contexts)},
+				{want: tt.wantClusters, got: lo.Keys(got.Clusters)},
+				{want: tt.wantAuthInfos, got: lo.Keys(got.AuthInfos)},
+				{want: []string{tt.wantCurrent}, got: []string{got.CurrentContext}},
+			} {
+				sort.Strings(item.got)
+				if !cmp.Equal(item.want, item.got) {
+					diff := cmp.Diff(item.want, item.got)
+					t.Errorf("source.filterContexts() mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if len(apiConfig.Contexts) != 4 {
+				t.Errorf("want the api config to be unchanged, got: '%d' contexts", len(apiConfig.Contexts))
+			}
+		})
+	}
+}
+
+func Test_LoadSource_Filter(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+
+	source := &config.SourceItem{
+		Name:    "vendor",
+		Include: []string{filepath.Join(caller, "..", "testdata", "0[12]-kontext-merge-*.yaml")},
+		Rename:  config.Rename{Prefix: "vendor-"},
+		Contexts: config.ContextFilter{
+			Include: []string{"vendor-*"},
+			Exclude: []string{"regex:.*merge-2"},
+		},
+	}
+
+	got, err := LoadSource(&config.Config{}, source, ExecRun)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	// the file, whose contexts are all filtered, is skipped
+	want := []string{filepath.Join(caller, "..", "testdata", "01-kontext-merge-1.yaml")}
+	files := lo.Map(got, func(item Kubeconfig, _ int) string {
+		return item.File
+	})
+	if !cmp.Equal(want, files) {
+		diff := cmp.Diff(want, files)
+		t.Errorf("source.LoadSource() mismatch (-want +got):\n%s", diff)
+	}
+}
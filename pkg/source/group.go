@@ -0,0 +1,200 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/samber/lo"
+)
+
+// GroupSources returns the names of all sources, that are referred by the given group and the groups it refers to,
+// excluded sources are removed. A group with a selector, but without sources and groups, refers to all sources.
+func GroupSources(currentConfig *config.Config, group *config.GroupItem) ([]string, error) {
+	return groupSources(currentConfig, group, nil)
+}
+
+func groupSources(currentConfig *config.Config, group *config.GroupItem, path []string) ([]string, error) {
+	path, err := visit(path, group.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer []string
+	for _, child := range referencedGroups(currentConfig, group) {
+		child := child
+		sources, err := groupSources(currentConfig, &child, path)
+		if err != nil {
+			return nil, err
+		}
+		buffer = append(buffer, sources...)
+	}
+	buffer = append(buffer, ownSources(currentConfig, group)...)
+
+	return lo.Without(lo.Uniq(buffer), group.ExcludeSources...), nil
+}
+
+// ownSources returns the names of the sources, that are referred by the given group itself
+func ownSources(currentConfig *config.Config, group *config.GroupItem) []string {
+	if len(group.Selector) == 0 || len(group.Sources) > 0 || len(group.Groups) > 0 {
+		return group.Sources
+	}
+	return lo.Map(currentConfig.Source.Items, func(item config.SourceItem, _ int) string {
+		return item.Name
+	})
+}
+
+// referencedGroups returns the groups, that are referred by the given group
+// Groups, that do not exist, are skipped with a warning.
+func referencedGroups(currentConfig *config.Config, group *config.GroupItem) []config.GroupItem {
+	log := logger.New()
+	var buffer []config.GroupItem
+
+	for _, groupName := range group.Groups {
+		groupMatch, ok := lo.Find(currentConfig.Group.Items, func(item config.GroupItem) bool {
+			return groupName == item.Name
+		})
+		if !ok {
+			log.Warn("could not find group", log.Args("group", groupName, "parent", group.Name))
+			continue
+		}
+		buffer = append(buffer, groupMatch)
+	}
+
+	return buffer
+}
+
+// loadGroup loads the contexts of all referenced groups and own sources, then removes the excluded sources,
+// all contexts, that do not match the selector, and all excluded contexts
+func loadGroup(currentConfig *config.Config, group *config.GroupItem, mode ExecMode, path []string) ([]Kubeconfig, error) {
+	log := logger.New()
+	var buffer []Kubeconfig
+
+	path, err := visit(path, group.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range referencedGroups(currentConfig, group) {
+		child := child
+		kubeconfigs, err := loadGroup(currentConfig, &child, mode, path)
+		if err != nil {
+			return nil, err
+		}
+		buffer = append(buffer, kubeconfigs...)
+	}
+
+	for _, sourceName := range ownSources(currentConfig, group) {
+		sourceMatch, ok := lo.Find(currentConfig.Source.Items, func(item config.SourceItem) bool {
+			return sourceName == item.Name
+		})
+		if !ok {
+			log.Warn("could not find source", log.Args("source", sourceName, "group", group.Name))
+			continue
+		}
+
+		kubeconfigs, err := LoadSource(currentConfig, &sourceMatch, mode)
+		if err != nil {
+			return nil, err
+		}
+		buffer = append(buffer, kubeconfigs...)
+	}
+
+	buffer = lo.Filter(deduplicate(buffer), func(item Kubeconfig, _ int) bool {
+		return !lo.Contains(group.ExcludeSources, item.Source)
+	})
+	if len(group.Selector) > 0 {
+		buffer, err = selectContexts(currentConfig, group, buffer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(group.ExcludeContexts) > 0 {
+		return excludeContexts(group, buffer)
+	}
+	return buffer, nil
+}
+
+// deduplicate merges all kubeconfigs, that have been loaded from the same file, e.g. by two referenced groups
+// with different selectors, the first occurrence keeps its position
+func deduplicate(kubeconfigs []Kubeconfig) []Kubeconfig {
+	var buffer []Kubeconfig
+	seen := map[string]int{}
+
+	for _, item := range kubeconfigs {
+		index, ok := seen[item.File]
+		if !ok {
+			seen[item.File] = len(buffer)
+			buffer = append(buffer, item)
+			continue
+		}
+		buffer[index].APIConfig, _ = kubeconfig.MergeConfigs(buffer[index].APIConfig, item.APIConfig)
+	}
+
+	return buffer
+}
+
+// excludeContexts removes all contexts from the given kubeconfigs, that match any exclude pattern of the group
+// Kubeconfigs without any remaining context are removed entirely.
+func excludeContexts(group *config.GroupItem, kubeconfigs []Kubeconfig) ([]Kubeconfig, error) {
+	var buffer []Kubeconfig
+
+	for _, item := range kubeconfigs {
+		apiConfig := item.APIConfig.DeepCopy()
+		for name := range apiConfig.Contexts {
+			for _, pattern := range group.ExcludeContexts {
+				match, err := doublestar.Match(pattern, name)
+				if err != nil {
+					return nil, fmt.Errorf("invalid context pattern '%s', err: '%w'", pattern, err)
+				}
+				if match {
+					delete(apiConfig.Contexts, name)
+					break
+				}
+			}
+		}
+		if len(apiConfig.Contexts) == 0 {
+			continue
+		}
+
+		item.APIConfig = kubeconfig.Prune(apiConfig)
+		buffer = append(buffer, item)
+	}
+
+	return buffer, nil
+}
+
+// visit appends the group to the path of the resolution, it fails if the group is already part of the path
+func visit(path []string, groupName string) ([]string, error) {
+	if lo.Contains(path, groupName) {
+		return nil, fmt.Errorf("could not resolve group '%s', err: 'cycle %s'", groupName, strings.Join(append(path, groupName), " -> "))
+	}
+	return append(path[:len(path):len(path)], groupName), nil
+}
+
+// HasSelector reports, whether the given group or any group it refers to selects its contexts by their labels
+func HasSelector(currentConfig *config.Config, group *config.GroupItem) (bool, error) {
+	return hasSelector(currentConfig, group, nil)
+}
+
+func hasSelector(currentConfig *config.Config, group *config.GroupItem, path []string) (bool, error) {
+	path, err := visit(path, group.Name)
+	if err != nil {
+		return false, err
+	}
+	if len(group.Selector) > 0 {
+		return true, nil
+	}
+
+	for _, child := range referencedGroups(currentConfig, group) {
+		child := child
+		ok, err := hasSelector(currentConfig, &child, path)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,178 @@
+package source
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+)
+
+func groupConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	currentConfig := labelConfig(t)
+	currentConfig.Group.Items = []config.GroupItem{
+		{Name: "dev", Sources: []string{"dev"}},
+		{Name: "prod", Selector: "env=prod"},
+		{Name: "team-a", Selector: "team=a"},
+		{Name: "all", Groups: []string{"dev", "prod"}},
+		{Name: "cycle-a", Groups: []string{"cycle-b"}},
+		{Name: "cycle-b", Sources: []string{"dev"}, Groups: []string{"cycle-a"}},
+	}
+	return currentConfig
+}
+
+func Test_GroupSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   config.GroupItem
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "should return the sources of the referenced groups before the own sources",
+			group: config.GroupItem{Name: "nested", Sources: []string{"prod"}, Groups: []string{"dev"}},
+			want:  []string{"dev", "prod"},
+		},
+		{
+			name:  "should return all sources of a referenced group with a selector",
+			group: config.GroupItem{Name: "nested", Groups: []string{"prod"}},
+			want:  []string{"dev", "prod"},
+		},
+		{
+			name:  "should return no sources for a group with a selector and groups",
+			group: config.GroupItem{Name: "nested", Selector: "env=prod", Groups: []string{"missing"}},
+			want:  []string{},
+		},
+		{
+			name:  "should remove the excluded sources and duplicates",
+			group: config.GroupItem{Name: "nested", Sources: []string{"dev"}, Groups: []string{"all"}, ExcludeSources: []string{"prod"}},
+			want:  []string{"dev"},
+		},
+		{
+			name:    "should throw an error, as the referenced groups contain a cycle",
+			group:   config.GroupItem{Name: "nested", Groups: []string{"cycle-a"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentConfig := groupConfig(t)
+
+			got, err := GroupSources(currentConfig, &tt.group)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("source.GroupSources() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_LoadGroup_Nested(t *testing.T) {
+	tests := []struct {
+		name      string
+		group     config.GroupItem
+		want      []string
+		wantFiles int
+		wantErr   bool
+	}{
+		{
+			name:      "should contain the contexts of all referenced groups",
+			group:     config.GroupItem{Name: "nested", Groups: []string{"dev", "prod"}},
+			want:      []string{"kind-kontext-merge-1", "kind-kontext-merge-2"},
+			wantFiles: 2,
+		},
+		{
+			name:      "should keep the selector of the referenced groups",
+			group:     config.GroupItem{Name: "nested", Groups: []string{"prod"}},
+			want:      []string{"kind-kontext-merge-2"},
+			wantFiles: 1,
+		},
+		{
+			name:      "should merge files, that are loaded by more than one referenced group",
+			group:     config.GroupItem{Name: "nested", Groups: []string{"prod", "team-a", "all"}},
+			want:      []string{"kind-kontext-merge-1", "kind-kontext-merge-2"},
+			wantFiles: 2,
+		},
+		{
+			name:      "should apply the own selector to the contexts of the referenced groups",
+			group:     config.GroupItem{Name: "nested", Groups: []string{"all"}, Selector: "env=dev"},
+			want:      []string{"kind-kontext-merge-1"},
+			wantFiles: 1,
+		},
+		{
+			name:      "should remove the excluded sources of the referenced groups",
+			group:     config.GroupItem{Name: "nested", Groups: []string{"all"}, ExcludeSources: []string{"prod"}},
+			want:      []string{"kind-kontext-merge-1"},
+			wantFiles: 1,
+		},
+		{
+			name:      "should remove the excluded contexts",
+			group:     config.GroupItem{Name: "nested", Groups: []string{"all"}, ExcludeContexts: []string{"*-2"}},
+			want:      []string{"kind-kontext-merge-1"},
+			wantFiles: 1,
+		},
+		{
+			name:      "should skip referenced groups, that do not exist",
+			group:     config.GroupItem{Name: "nested", Sources: []string{"dev"}, Groups: []string{"missing"}},
+			want:      []string{"kind-kontext-merge-1"},
+			wantFiles: 1,
+		},
+		{
+			name:    "should throw an error, as the referenced groups contain a cycle",
+			group:   config.GroupItem{Name: "nested", Groups: []string{"cycle-b"}},
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the group refers to itself",
+			group:   config.GroupItem{Name: "all", Groups: []string{"all"}},
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as an exclude pattern is invalid",
+			group:   config.GroupItem{Name: "nested", Groups: []string{"dev"}, ExcludeContexts: []string{"kind-["}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentConfig := groupConfig(t)
+
+			got, err := LoadGroup(currentConfig, &tt.group, ExecRun)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			contexts := []string{}
+			for _, item := range got {
+				for name := range item.APIConfig.Contexts {
+					contexts = append(contexts, name)
+				}
+			}
+			sort.Strings(contexts)
+			if !cmp.Equal(tt.want, contexts) {
+				diff := cmp.Diff(tt.want, contexts)
+				t.Errorf("source.LoadGroup() mismatch (-want +got):\n%s", diff)
+			}
+			if len(got) != tt.wantFiles {
+				t.Errorf("want '%d' files, got: '%d'", tt.wantFiles, len(got))
+			}
+		})
+	}
+}
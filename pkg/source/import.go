@@ -0,0 +1,119 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ImportTarget computes the path, that a file with the given name is imported to. Without a directory the static
+// part of the first include glob of the source is used. It fails, if the source would not include the path.
+func ImportTarget(source *config.SourceItem, directory string, name string) (string, error) {
+	if len(directory) == 0 {
+		if len(source.Include) == 0 {
+			return "", fmt.Errorf("source '%s' has no include glob, a directory is required", source.Name)
+		}
+		directory, _ = doublestar.SplitPattern(source.Include[0])
+	}
+
+	target, err := filepath.Abs(filepath.Join(directory, name))
+	if err != nil {
+		return "", fmt.Errorf("could not compute absolute path, err: '%w'", err)
+	}
+
+	included, err := matchAny(source.Include, target)
+	if err != nil {
+		return "", err
+	}
+	excluded, err := matchAny(source.Exclude, target)
+	if err != nil {
+		return "", err
+	}
+	if !included || excluded {
+		return "", fmt.Errorf("file '%s' would not be included by source '%s', choose another directory or name", target, source.Name)
+	}
+
+	return target, nil
+}
+
+// Import reads the kubeconfig file, renames its contexts and writes it to the target path. Relative paths are
+// resolved against the original file. Nothing is written, if the file would introduce conflicting names into the
+// source, the conflicts are returned instead.
+func Import(currentConfig *config.Config, source *config.SourceItem, file string, target string, contexts map[string]string) ([]kubeconfig.Conflict, error) {
+	apiConfig, err := read(file)
+	if err != nil {
+		return nil, err
+	}
+	err = kubeconfig.RenameContexts(apiConfig, contexts)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = os.Stat(target)
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("file '%s' already exists", target)
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("could not check file '%s', err: '%w'", target, err)
+	}
+
+	// conflicts are reported with the names, that the rename rules of the source produce
+	candidate := apiConfig.DeepCopy()
+	kubeconfig.SetLocationOfOrigin(candidate, target)
+	err = rename(source, target, candidate)
+	if err != nil {
+		return nil, fmt.Errorf("could not rename kubeconfig '%s' of source '%s', err: '%w'", target, source.Name, err)
+	}
+	kubeconfigs, err := LoadSource(currentConfig, source, ExecCached)
+	if err != nil {
+		return nil, err
+	}
+	apiConfigs := lo.Map(kubeconfigs, func(item Kubeconfig, _ int) *api.Config {
+		return item.APIConfig
+	})
+	_, conflicts := kubeconfig.MergeConfigs(append(apiConfigs, candidate)...)
+	conflicts = lo.Filter(conflicts, func(item kubeconfig.Conflict, _ int) bool {
+		return lo.Contains(item.Files, target)
+	})
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(target), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create directory, err: '%w'", err)
+	}
+	return nil, kubeconfig.WriteFile(target, apiConfig)
+}
+
+// read validates the kubeconfig file, it has to define at least one context
+func read(path string) (*api.Config, error) {
+	apiConfig, err := kubeconfig.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(apiConfig.Contexts) == 0 {
+		return nil, fmt.Errorf("kubeconfig '%s' does not define any context", path)
+	}
+	return apiConfig, nil
+}
+
+func matchAny(patterns []string, path string) (bool, error) {
+	for _, pattern := range patterns {
+		match, err := doublestar.PathMatch(pattern, path)
+		if err != nil {
+			return false, fmt.Errorf("invalid glob '%s', err: '%w'", pattern, err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
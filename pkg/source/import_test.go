@@ -0,0 +1,208 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func Test_ImportTarget(t *testing.T) {
+	directory := t.TempDir()
+
+	tests := []struct {
+		name      string
+		source    *config.SourceItem
+		directory string
+		file      string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "should use the static part of the first include glob",
+			source: &config.SourceItem{
+				Name:    "dev",
+				Include: []string{filepath.Join(directory, "dev", "**", "*.yaml")},
+			},
+			file: "kind.yaml",
+			want: filepath.Join(directory, "dev", "kind.yaml"),
+		},
+		{
+			name: "should use the given directory, as it is included by the source",
+			source: &config.SourceItem{
+				Name:    "dev",
+				Include: []string{filepath.Join(directory, "dev", "**", "*.yaml")},
+			},
+			directory: filepath.Join(directory, "dev", "customer"),
+			file:      "kind.yaml",
+			want:      filepath.Join(directory, "dev", "customer", "kind.yaml"),
+		},
+		{
+			name: "should throw an error, as the source would not include the file",
+			source: &config.SourceItem{
+				Name:    "dev",
+				Include: []string{filepath.Join(directory, "dev", "**", "*.yaml")},
+			},
+			file:    "kind.conf",
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as the source would exclude the file",
+			source: &config.SourceItem{
+				Name:    "dev",
+				Include: []string{filepath.Join(directory, "dev", "**", "*.yaml")},
+				Exclude: []string{filepath.Join(directory, "dev", "**", "*prod*.yaml")},
+			},
+			file:    "kind-prod.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "should throw an error, as the source has no include glob",
+			source:  &config.SourceItem{Name: "cloud"},
+			file:    "kind.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ImportTarget(tt.source, tt.directory, tt.file)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.want != got {
+				t.Errorf("want: '%s', got: '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Import(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	testdata := filepath.Join(caller, "..", "testdata")
+
+	type args struct {
+		file     string
+		contexts map[string]string
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantContexts  []string
+		wantCA        string
+		wantConflicts int
+		wantWritten   bool
+		wantErr       bool
+	}{
+		{
+			name:         "should import the file",
+			args:         args{file: filepath.Join(testdata, "02-kontext-merge-2.yaml")},
+			wantContexts: []string{"kind-kontext-merge-2"},
+			wantWritten:  true,
+		},
+		{
+			name: "should import the file, rename its context and resolve its relative paths",
+			args: args{
+				file:     filepath.Join(testdata, "07-kontext-import-relative.yaml"),
+				contexts: map[string]string{"kind-kontext-import": "kind-dev"},
+			},
+			wantContexts: []string{"kind-dev"},
+			wantCA:       filepath.Join(testdata, "certificates", "ca.crt"),
+			wantWritten:  true,
+		},
+		{
+			name: "should not import the file, as it conflicts with an existing file of the source",
+			args: args{file: filepath.Join(testdata, "05-kontext-conflict.yaml")},
+			// the context is identical, only the cluster and the user differ
+			wantConflicts: 2,
+		},
+		{
+			name:    "should throw an error, as the file is not a valid kubeconfig",
+			args:    args{file: filepath.Join(testdata, "04-kontext-merge-invalid.yaml")},
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as the context to rename does not exist",
+			args: args{
+				file:     filepath.Join(testdata, "02-kontext-merge-2.yaml"),
+				contexts: map[string]string{"kind-kontext-merge-3": "kind-dev"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := t.TempDir()
+			existing, err := os.ReadFile(filepath.Join(testdata, "01-kontext-merge-1.yaml"))
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			err = os.WriteFile(filepath.Join(directory, "existing.yaml"), existing, 0600)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			source := &config.SourceItem{
+				Name:    "dev",
+				Include: []string{filepath.Join(directory, "**", "*.yaml")},
+			}
+			target := filepath.Join(directory, "imported", "kubeconfig.yaml")
+
+			conflicts, err := Import(&config.Config{}, source, tt.args.file, target, tt.args.contexts)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if len(conflicts) != tt.wantConflicts {
+				t.Errorf("want conflicts: '%d', got: '%v'", tt.wantConflicts, conflicts)
+			}
+			for _, conflict := range conflicts {
+				if !lo.Contains(conflict.Files, target) {
+					t.Errorf("want conflict with file: '%s', got: '%v'", target, conflict)
+				}
+			}
+
+			_, err = os.Stat(target)
+			if tt.wantWritten != (err == nil) {
+				t.Errorf("want written: '%t', got: '%v'", tt.wantWritten, err)
+			}
+			if !tt.wantWritten {
+				return
+			}
+
+			apiConfig, err := clientcmd.LoadFromFile(target)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			for _, contextName := range tt.wantContexts {
+				if _, ok := apiConfig.Contexts[contextName]; !ok {
+					t.Errorf("want context: '%s', got: '%v'", contextName, lo.Keys(apiConfig.Contexts))
+				}
+			}
+			if len(tt.wantCA) > 0 && apiConfig.Clusters["kind-kontext-import"].CertificateAuthority != tt.wantCA {
+				t.Errorf("want certificate authority: '%s', got: '%s'", tt.wantCA, apiConfig.Clusters["kind-kontext-import"].CertificateAuthority)
+			}
+
+			// the imported file is part of the source afterwards
+			kubeconfigs, err := LoadSource(&config.Config{}, source, ExecRun)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if len(kubeconfigs) != 2 {
+				t.Errorf("want two files within the source, got: '%d'", len(kubeconfigs))
+			}
+		})
+	}
+}
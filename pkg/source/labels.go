@@ -0,0 +1,85 @@
+package source
+
+import (
+	"fmt"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/label"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ComputeLabels returns the labels of all contexts, that are defined by the given kubeconfigs
+// The labels of the source are overridden by the labels of matching label items, which are overridden by the labels,
+// that have been set with `kontext label context`. The first kubeconfig, that defines a context, takes precedence.
+func ComputeLabels(currentConfig *config.Config, kubeconfigs []Kubeconfig) (map[string]labels.Set, error) {
+	buffer := map[string]labels.Set{}
+
+	store, err := label.Read(currentConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range kubeconfigs {
+		fileLabels := labels.Set{}
+
+		source, ok := lo.Find(currentConfig.Source.Items, func(source config.SourceItem) bool {
+			return source.Name == item.Source
+		})
+		if ok {
+			fileLabels = labels.Merge(fileLabels, source.Labels)
+		}
+		for _, labelItem := range currentConfig.Label.Items {
+			match, err := doublestar.PathMatch(labelItem.File, item.File)
+			if err != nil {
+				return nil, fmt.Errorf("could not match label file pattern '%s', err: '%w'", labelItem.File, err)
+			}
+			if match {
+				fileLabels = labels.Merge(fileLabels, labelItem.Labels)
+			}
+		}
+
+		for name := range item.APIConfig.Contexts {
+			if _, ok := buffer[name]; ok {
+				continue
+			}
+			buffer[name] = labels.Merge(fileLabels, store[name])
+		}
+	}
+
+	return buffer, nil
+}
+
+// selectContexts removes all contexts from the given kubeconfigs, that do not match the selector of the group
+// Kubeconfigs without any matching context are removed entirely.
+func selectContexts(currentConfig *config.Config, group *config.GroupItem, kubeconfigs []Kubeconfig) ([]Kubeconfig, error) {
+	selector, err := labels.Parse(group.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse selector of group '%s', err: '%w'", group.Name, err)
+	}
+
+	contextLabels, err := ComputeLabels(currentConfig, kubeconfigs)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer []Kubeconfig
+	for _, item := range kubeconfigs {
+		apiConfig := item.APIConfig.DeepCopy()
+		for name := range apiConfig.Contexts {
+			if !selector.Matches(contextLabels[name]) {
+				delete(apiConfig.Contexts, name)
+			}
+		}
+		if len(apiConfig.Contexts) == 0 {
+			continue
+		}
+
+		item.APIConfig = kubeconfig.Prune(apiConfig)
+		buffer = append(buffer, item)
+	}
+
+	return buffer, nil
+}
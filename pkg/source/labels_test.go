@@ -0,0 +1,139 @@
+package source
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/label"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func labelConfig(t *testing.T) *config.Config {
+	t.Helper()
+	_, caller, _, _ := runtime.Caller(0)
+
+	currentConfig := &config.Config{
+		Source: config.Source{
+			Items: []config.SourceItem{
+				{
+					Name:    "dev",
+					Include: []string{filepath.Join(caller, "..", "testdata", "01-kontext-merge-1.yaml")},
+					Labels:  map[string]string{"env": "dev", "team": "a"},
+				},
+				{
+					Name:    "prod",
+					Include: []string{filepath.Join(caller, "..", "testdata", "02-kontext-merge-2.yaml")},
+					Labels:  map[string]string{"env": "prod"},
+				},
+			},
+		},
+		Label: config.Label{
+			File: filepath.Join(t.TempDir(), "labels.json"),
+			Items: []config.LabelItem{
+				{File: "**/02-*.yaml", Labels: map[string]string{"team": "a", "tier": "gold"}},
+			},
+		},
+	}
+
+	err := label.Write(currentConfig, label.Store{
+		"kind-kontext-merge-1": {"team": "b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	return currentConfig
+}
+
+func Test_ComputeLabels(t *testing.T) {
+	currentConfig := labelConfig(t)
+
+	kubeconfigs, err := LoadGroup(currentConfig, &config.GroupItem{Name: "all", Sources: []string{"dev", "prod"}}, ExecRun)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	got, err := ComputeLabels(currentConfig, kubeconfigs)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	want := map[string]labels.Set{
+		"kind-kontext-merge-1": {"env": "dev", "team": "b"},
+		"kind-kontext-merge-2": {"env": "prod", "team": "a", "tier": "gold"},
+	}
+	if !cmp.Equal(want, got) {
+		diff := cmp.Diff(want, got)
+		t.Errorf("source.ComputeLabels() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_LoadGroup_Selector(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   config.GroupItem
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "should select the matching contexts of all sources, as the group has no sources",
+			group: config.GroupItem{Name: "prod", Selector: "env=prod"},
+			want:  []string{"kind-kontext-merge-2"},
+		},
+		{
+			name:  "should select the contexts by a set based selector",
+			group: config.GroupItem{Name: "teams", Selector: "team in (a,b),env!=staging"},
+			want:  []string{"kind-kontext-merge-1", "kind-kontext-merge-2"},
+		},
+		{
+			name:  "should only select the contexts of the given sources",
+			group: config.GroupItem{Name: "teams", Sources: []string{"prod"}, Selector: "team in (a,b)"},
+			want:  []string{"kind-kontext-merge-2"},
+		},
+		{
+			name:  "should select no context, as no context matches",
+			group: config.GroupItem{Name: "staging", Selector: "env=staging"},
+			want:  []string{},
+		},
+		{
+			name:    "should throw an error, as the selector is invalid",
+			group:   config.GroupItem{Name: "invalid", Selector: "env in (dev"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentConfig := labelConfig(t)
+
+			got, err := LoadGroup(currentConfig, &tt.group, ExecRun)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			contexts := []string{}
+			for _, item := range got {
+				for name := range item.APIConfig.Contexts {
+					contexts = append(contexts, name)
+				}
+				if len(item.APIConfig.Clusters) != len(item.APIConfig.Contexts) {
+					t.Errorf("want only the clusters of the selected contexts, got: '%d'", len(item.APIConfig.Clusters))
+				}
+			}
+			sort.Strings(contexts)
+			if !cmp.Equal(tt.want, contexts) {
+				diff := cmp.Diff(tt.want, contexts)
+				t.Errorf("source.LoadGroup() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
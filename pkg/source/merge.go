@@ -0,0 +1,136 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/samber/lo"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Kubeconfig is a single file of a source, all names are already rewritten by the rename rules of the source
+type Kubeconfig struct {
+	File      string
+	Source    string
+	APIConfig *api.Config
+}
+
+// renameData is passed to the rename template of a source
+type renameData struct {
+	Name   string
+	Source string
+	File   string
+}
+
+// LoadGroup reads all files of the sources and groups, that are referred by the given group, and applies their
+// rename rules. If the group has a selector, only matching contexts are kept, excluded contexts are removed.
+func LoadGroup(currentConfig *config.Config, group *config.GroupItem, mode ExecMode) ([]Kubeconfig, error) {
+	return loadGroup(currentConfig, group, mode, nil)
+}
+
+// LoadSource reads all files of the given source, applies its rename rules and filters its contexts
+func LoadSource(currentConfig *config.Config, source *config.SourceItem, mode ExecMode) ([]Kubeconfig, error) {
+	log := logger.New()
+	var buffer []Kubeconfig
+
+	files, err := computeSource(currentConfig, source, mode)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		_ = file.Close()
+	}
+
+	for _, file := range files {
+		apiConfig, err := clientcmd.LoadFromFile(file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("could not load kubeconfig '%s' of source '%s', err: '%w'", file.Name(), source.Name, err)
+		}
+		err = clientcmd.ResolveLocalPaths(apiConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve local paths, err: '%w'", err)
+		}
+
+		err = rename(source, file.Name(), apiConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not rename kubeconfig '%s' of source '%s', err: '%w'", file.Name(), source.Name, err)
+		}
+
+		apiConfig, err = filterContexts(source, apiConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not filter kubeconfig '%s' of source '%s', err: '%w'", file.Name(), source.Name, err)
+		}
+		if len(apiConfig.Contexts) == 0 && len(source.Contexts.Include)+len(source.Contexts.Exclude) > 0 {
+			log.Debug("skipping kubeconfig, as all contexts are filtered", log.Args("file", file.Name(), "source", source.Name))
+			continue
+		}
+
+		buffer = append(buffer, Kubeconfig{
+			File:      file.Name(),
+			Source:    source.Name,
+			APIConfig: apiConfig,
+		})
+	}
+
+	return buffer, nil
+}
+
+// MergeGroup merges all files of the given group, conflicting names are returned alongside the result
+func MergeGroup(currentConfig *config.Config, group *config.GroupItem, mode ExecMode) (*api.Config, []kubeconfig.Conflict, error) {
+	kubeconfigs, err := LoadGroup(currentConfig, group, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apiConfig, conflicts := Merge(kubeconfigs)
+	return apiConfig, conflicts, nil
+}
+
+// Merge merges the given kubeconfigs, that have been loaded before, conflicting names are returned alongside the result
+func Merge(kubeconfigs []Kubeconfig) (*api.Config, []kubeconfig.Conflict) {
+	return kubeconfig.MergeConfigs(lo.Map(kubeconfigs, func(item Kubeconfig, _ int) *api.Config {
+		return item.APIConfig
+	})...)
+}
+
+// rename applies the rename rules of the source to all names of the api config
+func rename(source *config.SourceItem, file string, apiConfig *api.Config) error {
+	rules := source.Rename
+	if len(rules.Template) == 0 && len(rules.Prefix) == 0 && len(rules.Suffix) == 0 {
+		return nil
+	}
+
+	var instance *template.Template
+	if len(rules.Template) > 0 {
+		var err error
+		instance, err = template.New(source.Name).Option("missingkey=error").Parse(rules.Template)
+		if err != nil {
+			return fmt.Errorf("could not parse rename template, err: '%w'", err)
+		}
+	}
+	base := filepath.Base(file)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	return kubeconfig.Rename(apiConfig, func(name string) (string, error) {
+		if instance != nil {
+			var buffer bytes.Buffer
+			err := instance.Execute(&buffer, renameData{
+				Name:   name,
+				Source: source.Name,
+				File:   base,
+			})
+			if err != nil {
+				return "", fmt.Errorf("could not execute rename template, err: '%w'", err)
+			}
+			name = buffer.String()
+		}
+		return rules.Prefix + name + rules.Suffix, nil
+	})
+}
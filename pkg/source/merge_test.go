@@ -0,0 +1,132 @@
+package source
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/samber/lo"
+)
+
+func Test_MergeGroup(t *testing.T) {
+	_, caller, _, _ := runtime.Caller(0)
+	merge := filepath.Join(caller, "..", "testdata", "01-kontext-merge-1.yaml")
+	conflict := filepath.Join(caller, "..", "testdata", "05-kontext-conflict.yaml")
+	malformed := filepath.Join(caller, "..", "testdata", "08-kontext-malformed.yaml")
+
+	type want struct {
+		contexts       []string
+		currentContext string
+		conflicts      []kubeconfig.Conflict
+	}
+	tests := []struct {
+		name    string
+		sources []config.SourceItem
+		want    want
+		wantErr bool
+	}{
+		{
+			name: "should report all conflicting names, the first file wins",
+			sources: []config.SourceItem{
+				{Name: "merge", Include: []string{merge}},
+				{Name: "conflict", Include: []string{conflict}},
+			},
+			want: want{
+				contexts:       []string{"kind-kontext-merge-1"},
+				currentContext: "kind-kontext-merge-1",
+				conflicts: []kubeconfig.Conflict{
+					{Kind: kubeconfig.KindCluster, Name: "kind-kontext-merge-1", Files: []string{merge, conflict}},
+					{Kind: kubeconfig.KindUser, Name: "kind-kontext-merge-1", Files: []string{merge, conflict}},
+				},
+			},
+		},
+		{
+			name: "should keep all contexts, as the second source is renamed by prefix and suffix",
+			sources: []config.SourceItem{
+				{Name: "merge", Include: []string{merge}},
+				{Name: "conflict", Include: []string{conflict}, Rename: config.Rename{Prefix: "customer-", Suffix: "-admin"}},
+			},
+			want: want{
+				contexts:       []string{"customer-kind-kontext-merge-1-admin", "kind-kontext-merge-1"},
+				currentContext: "kind-kontext-merge-1",
+			},
+		},
+		{
+			name: "should keep all contexts, as the second source is renamed by template",
+			sources: []config.SourceItem{
+				{Name: "merge", Include: []string{merge}},
+				{Name: "conflict", Include: []string{conflict}, Rename: config.Rename{Template: "{{ .Source }}-{{ .File }}-{{ .Name }}"}},
+			},
+			want: want{
+				contexts:       []string{"conflict-05-kontext-conflict-kind-kontext-merge-1", "kind-kontext-merge-1"},
+				currentContext: "kind-kontext-merge-1",
+			},
+		},
+		{
+			name: "should throw an error, as the template refers to an unknown field",
+			sources: []config.SourceItem{
+				{Name: "conflict", Include: []string{conflict}, Rename: config.Rename{Template: "{{ .Cluster }}"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should throw an error, as a kubeconfig of the source is malformed",
+			sources: []config.SourceItem{
+				{Name: "merge", Include: []string{merge}},
+				{Name: "malformed", Include: []string{malformed}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentConfig := &config.Config{
+				Source: config.Source{Items: tt.sources},
+			}
+			group := &config.GroupItem{
+				Name: "customers",
+				Sources: lo.Map(tt.sources, func(item config.SourceItem, _ int) string {
+					return item.Name
+				}),
+			}
+
+			apiConfig, conflicts, err := MergeGroup(currentConfig, group, ExecRun)
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			contexts := lo.Keys(apiConfig.Contexts)
+			sort.Strings(contexts)
+			got := want{
+				contexts:       contexts,
+				currentContext: apiConfig.CurrentContext,
+				conflicts:      conflicts,
+			}
+			if !cmp.Equal(tt.want, got, cmp.AllowUnexported(want{})) {
+				diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(want{}))
+				t.Errorf("source.MergeGroup() mismatch (-want +got):\n%s", diff)
+			}
+
+			// every context has to refer to the renamed cluster and user of its own file
+			for name, context := range apiConfig.Contexts {
+				if _, ok := apiConfig.Clusters[context.Cluster]; !ok {
+					t.Errorf("context '%s' refers to missing cluster '%s'", name, context.Cluster)
+				}
+				if _, ok := apiConfig.AuthInfos[context.AuthInfo]; !ok {
+					t.Errorf("context '%s' refers to missing user '%s'", name, context.AuthInfo)
+				}
+			}
+		})
+	}
+}
@@ -36,6 +36,66 @@ func ComputeFiles(source *config.SourceItem) ([]*os.File, error) {
 	return buffer, nil
 }
 
+// ComputeGroupFiles computes the target files for all sources, that are referred by the given group
+// Sources, that do not exist, are skipped with a warning.
+func ComputeGroupFiles(currentConfig *config.Config, group *config.GroupItem, mode ExecMode) ([]*os.File, error) {
+	log := logger.New()
+	var buffer []*os.File
+
+	sources, err := GroupSources(currentConfig, group)
+	if err != nil {
+		return nil, err
+	}
+	for _, sourceName := range sources {
+		sourceMatch, ok := lo.Find(currentConfig.Source.Items, func(item config.SourceItem) bool {
+			return sourceName == item.Name
+		})
+		if !ok {
+			log.Warn("could not find source", log.Args("source", sourceName, "group", group.Name))
+			continue
+		}
+		match, err := computeSource(currentConfig, &sourceMatch, mode)
+		if err != nil {
+			return nil, err
+		}
+		buffer = append(buffer, match...)
+	}
+
+	return buffer, nil
+}
+
+// computeSource computes the files of the source, the kubeconfig of an exec source is appended to its files
+func computeSource(currentConfig *config.Config, source *config.SourceItem, mode ExecMode) ([]*os.File, error) {
+	buffer, err := ComputeFiles(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(source.Exec.Command) == 0 {
+		return buffer, nil
+	}
+
+	// listings and the previews of fzf, that run once per item, only read the cached kubeconfig
+	_, preview := os.LookupEnv(config.PreviewEnvironmentVariable)
+	if mode == ExecCached || preview {
+		match, err := cachedExec(currentConfig, source)
+		if err != nil {
+			log := logger.New()
+			log.Debug("skipping exec source without cached kubeconfig", log.Args("source", source.Name, "error", err.Error()))
+			return buffer, nil
+		}
+		return append(buffer, match), nil
+	}
+
+	match, err := ComputeExec(currentConfig, source)
+	if err != nil {
+		for _, file := range buffer {
+			_ = file.Close()
+		}
+		return nil, err
+	}
+	return append(buffer, match), nil
+}
+
 func computeIncludes(source *config.SourceItem) ([]*os.File, error) {
 	log := logger.New()
 	var buffer []*os.File
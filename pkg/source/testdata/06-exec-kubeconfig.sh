@@ -0,0 +1,32 @@
+#!/bin/sh
+# prints a kubeconfig, every invocation is recorded in $KONTEXT_TEST_COUNTER
+echo "run" >> "$KONTEXT_TEST_COUNTER"
+
+if [ "$1" = "fail" ] || [ -n "$KONTEXT_TEST_FAIL" ]; then
+  echo "could not generate kubeconfig" >&2
+  exit 1
+fi
+
+if [ "$1" = "invalid" ]; then
+  echo "kontext"
+  exit 0
+fi
+
+cat <<KUBECONFIG
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: $1
+contexts:
+- context:
+    cluster: $1
+    user: $1
+  name: $1
+current-context: $1
+users:
+- name: $1
+  user:
+    token: kontext
+KUBECONFIG
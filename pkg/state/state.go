@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/parsers/yaml"
-	"github.com/knadh/koanf/providers/file"
+	koanfFile "github.com/knadh/koanf/providers/file"
 	"github.com/orbatschow/kontext/pkg/config"
 	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/utils/file"
 )
 
 type History string
@@ -19,24 +21,50 @@ type Revision string
 type Group struct {
 	Active  string    `json:"active,omitempty"`
 	History []History `json:"history,omitempty"`
+	// Usage is used to sort the group selection by recent use or frecency
+	Usage map[string]Usage `json:"usage,omitempty"`
+	// Contexts maps each group to its last active context, which is restored when switching back to the group
+	Contexts map[string]string `json:"contexts,omitempty"`
 }
 
 type Context struct {
 	Active  string    `json:"active,omitempty"`
 	History []History `json:"history,omitempty"`
+	// Activated is the time, the active context has been set
+	Activated *time.Time `json:"activated,omitempty"`
+	// Usage is used to sort the context selection by recent use or frecency
+	Usage map[string]Usage `json:"usage,omitempty"`
+}
+
+type Namespace struct {
+	// Active maps each context to its active namespace
+	Active map[string]string `json:"active,omitempty"`
+	// History maps each context to its namespace history, so the previous namespace is the one of the same context
+	History map[string][]History `json:"history,omitempty"`
+}
+
+// Snapshot describes the active group and context at the time a backup revision was created
+type Snapshot struct {
+	Group   string `json:"group,omitempty"`
+	Context string `json:"context,omitempty"`
 }
 
 type Backup struct {
-	Revisions []Revision `json:"revisions,omitempty"`
+	Revisions []Revision            `json:"revisions,omitempty"`
+	Snapshots map[Revision]Snapshot `json:"snapshots,omitempty"`
 }
 
 type State struct {
-	Group   Group   `json:"group"`
-	Context Context `json:"context"`
-	Backup  Backup  `json:"backup"`
+	Group     Group     `json:"group"`
+	Context   Context   `json:"context"`
+	Namespace Namespace `json:"namespace"`
+	Backup    Backup    `json:"backup"`
 }
 
-const DefaultMaximumHistorySize = 10
+const (
+	DefaultMaximumHistorySize = 10
+	DefaultLockTimeout        = 10 * time.Second
+)
 
 // Init checks if the state directory exists and creates all directories and files if necessary
 func Init(config *config.Config) error {
@@ -73,7 +101,7 @@ func Read(config *config.Config) (*State, error) {
 	var state *State
 
 	// load the state file into koanf
-	if err := instance.Load(file.Provider(config.State.File), yaml.Parser()); err != nil {
+	if err := instance.Load(koanfFile.Provider(config.State.File), yaml.Parser()); err != nil {
 		return nil, err
 	}
 
@@ -99,7 +127,7 @@ func Write(config *config.Config, state *State) error {
 	log.Trace("updating state", log.Args("data", string(buffer)))
 
 	// write the state into the state file
-	err = os.WriteFile(config.State.File, buffer, 0600)
+	err = file.WriteAtomic(config.State.File, buffer, 0600)
 	if err != nil {
 		return fmt.Errorf("could not write state to file, err: '%w'", err)
 	}
@@ -108,6 +136,24 @@ func Write(config *config.Config, state *State) error {
 	return nil
 }
 
+// Lock acquires the lock, that guards the read-modify-write cycle of the kubeconfig and state file
+func Lock(config *config.Config) (*file.Lock, error) {
+	log := logger.New()
+
+	timeout := config.State.Lock.Timeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+
+	lock, err := file.Acquire(config.State.File+".lock", timeout)
+	if err != nil {
+		return nil, fmt.Errorf("another kontext process is modifying the kubeconfig, err: '%w'", err)
+	}
+	log.Trace("acquired state lock", log.Args("path", config.State.File+".lock"))
+
+	return lock, nil
+}
+
 // ComputeHistory takes the current history and appends a new entry
 // If the history size is larger than the configured or default size, it will remove
 // the oldest entry from the history
@@ -123,3 +169,19 @@ func ComputeHistory(config *config.Config, entry History, history []History) []H
 
 	return history
 }
+
+// ComputeContexts remembers the given context as the last active context of the given group
+// Without a group or context, the contexts are returned unchanged.
+func ComputeContexts(groupName string, contextName string, contexts map[string]string) map[string]string {
+	if len(groupName) == 0 || len(contextName) == 0 {
+		return contexts
+	}
+
+	buffer := map[string]string{}
+	for name, value := range contexts {
+		buffer[name] = value
+	}
+	buffer[groupName] = contextName
+
+	return buffer
+}
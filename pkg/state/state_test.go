@@ -194,7 +194,7 @@ func Test_Write(t *testing.T) {
 					},
 				},
 			},
-			want:    []byte(`{"group":{"active":"dev"},"context":{"active":"kind-dev"},"backup":{}}`),
+			want:    []byte(`{"group":{"active":"dev"},"context":{"active":"kind-dev"},"namespace":{},"backup":{}}`),
 			wantErr: false,
 		},
 		{
@@ -220,7 +220,7 @@ func Test_Write(t *testing.T) {
 					},
 				},
 			},
-			want:    []byte(`{"group":{"active":"dev","history":["dev"]},"context":{},"backup":{}}`),
+			want:    []byte(`{"group":{"active":"dev","history":["dev"]},"context":{},"namespace":{},"backup":{}}`),
 			wantErr: false,
 		},
 	}
@@ -402,3 +402,49 @@ func Test_ComputeHistory(t *testing.T) {
 		})
 	}
 }
+
+func Test_ComputeContexts(t *testing.T) {
+	contexts := map[string]string{"dev": "kind-dev"}
+
+	var tests = []struct {
+		name      string
+		groupName string
+		context   string
+		want      map[string]string
+	}{
+		{
+			name:      "should remember the context of a new group",
+			groupName: "prod",
+			context:   "kind-prod",
+			want:      map[string]string{"dev": "kind-dev", "prod": "kind-prod"},
+		},
+		{
+			name:      "should replace the context of an existing group",
+			groupName: "dev",
+			context:   "kind-local",
+			want:      map[string]string{"dev": "kind-local"},
+		},
+		{
+			name:    "should keep the contexts, as there is no group",
+			context: "kind-prod",
+			want:    map[string]string{"dev": "kind-dev"},
+		},
+		{
+			name:      "should keep the contexts, as there is no context",
+			groupName: "prod",
+			want:      map[string]string{"dev": "kind-dev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeContexts(tt.groupName, tt.context, contexts)
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("state.ComputeContexts() mismatch (-want +got):\n%s", diff)
+			}
+			if len(contexts) != 1 {
+				t.Errorf("want the given contexts to be unchanged, got: '%v'", contexts)
+			}
+		})
+	}
+}
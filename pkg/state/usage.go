@@ -0,0 +1,69 @@
+package state
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultUsageRetention is the duration, after which unused entries are removed from the usage
+const DefaultUsageRetention = 90 * 24 * time.Hour
+
+// Usage counts how often an entry has been set and when it has been set the last time
+type Usage struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// ComputeUsage counts the given entry as used at the given time
+// Entries, that have not been used within the retention, are removed.
+func ComputeUsage(entry string, usage map[string]Usage, now time.Time) map[string]Usage {
+	buffer := map[string]Usage{}
+	for name, value := range usage {
+		if now.Sub(value.Last) < DefaultUsageRetention {
+			buffer[name] = value
+		}
+	}
+
+	value := buffer[entry]
+	buffer[entry] = Usage{
+		Count: value.Count + 1,
+		Last:  now,
+	}
+
+	return buffer
+}
+
+// Frecency weighs the use count by the age of the last use, recently used entries weigh more
+func (u Usage) Frecency(now time.Time) float64 {
+	age := now.Sub(u.Last)
+	switch {
+	case age < time.Hour:
+		return float64(u.Count) * 4
+	case age < 24*time.Hour:
+		return float64(u.Count) * 2
+	case age < 7*24*time.Hour:
+		return float64(u.Count)
+	default:
+		return float64(u.Count) / 2
+	}
+}
+
+// SortRecent sorts the given names by their last use, the most recently used name comes first
+// Unused names keep their order and are placed after all used names.
+func SortRecent(names []string, usage map[string]Usage) {
+	sort.SliceStable(names, func(i, j int) bool {
+		return usage[names[i]].Last.After(usage[names[j]].Last)
+	})
+}
+
+// SortFrecency sorts the given names by their frecency, the last use decides between names with the same frecency
+// Unused names keep their order and are placed after all used names.
+func SortFrecency(names []string, usage map[string]Usage, now time.Time) {
+	sort.SliceStable(names, func(i, j int) bool {
+		left, right := usage[names[i]], usage[names[j]]
+		if left.Frecency(now) != right.Frecency(now) {
+			return left.Frecency(now) > right.Frecency(now)
+		}
+		return left.Last.After(right.Last)
+	})
+}
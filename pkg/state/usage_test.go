@@ -0,0 +1,120 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ComputeUsage(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		entry string
+		usage map[string]Usage
+		want  map[string]Usage
+	}{
+		{
+			name:  "should add a new entry to an empty usage",
+			entry: "dev",
+			want: map[string]Usage{
+				"dev": {Count: 1, Last: now},
+			},
+		},
+		{
+			name:  "should increase the count and update the last use of an existing entry",
+			entry: "dev",
+			usage: map[string]Usage{
+				"dev":  {Count: 3, Last: now.Add(-time.Hour)},
+				"prod": {Count: 1, Last: now.Add(-time.Hour)},
+			},
+			want: map[string]Usage{
+				"dev":  {Count: 4, Last: now},
+				"prod": {Count: 1, Last: now.Add(-time.Hour)},
+			},
+		},
+		{
+			name:  "should remove all entries, that exceed the retention",
+			entry: "dev",
+			usage: map[string]Usage{
+				"prod": {Count: 10, Last: now.Add(-DefaultUsageRetention)},
+			},
+			want: map[string]Usage{
+				"dev": {Count: 1, Last: now},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeUsage(tt.entry, tt.usage, now)
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("state.ComputeUsage() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_SortRecent(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	names := []string{"a", "b", "c", "d"}
+	usage := map[string]Usage{
+		"b": {Count: 10, Last: now.Add(-24 * time.Hour)},
+		"d": {Count: 1, Last: now},
+	}
+
+	SortRecent(names, usage)
+	want := []string{"d", "b", "a", "c"}
+	if !cmp.Equal(want, names) {
+		diff := cmp.Diff(want, names)
+		t.Errorf("state.SortRecent() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_SortFrecency(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		usage map[string]Usage
+		want  []string
+	}{
+		{
+			name: "should sort the most frequently used name first",
+			usage: map[string]Usage{
+				"b": {Count: 2, Last: now.Add(-2 * time.Hour)},
+				"c": {Count: 5, Last: now.Add(-2 * time.Hour)},
+			},
+			want: []string{"c", "b", "a", "d"},
+		},
+		{
+			name: "should weigh recently used names more",
+			usage: map[string]Usage{
+				"b": {Count: 3, Last: now.Add(-10 * time.Minute)},
+				"c": {Count: 10, Last: now.Add(-30 * 24 * time.Hour)},
+			},
+			want: []string{"b", "c", "a", "d"},
+		},
+		{
+			name: "should sort the most recently used name first, as the frecency is equal",
+			usage: map[string]Usage{
+				"a": {Count: 1, Last: now.Add(-3 * time.Hour)},
+				"d": {Count: 1, Last: now.Add(-2 * time.Hour)},
+			},
+			want: []string{"d", "a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := []string{"a", "b", "c", "d"}
+			SortFrecency(names, tt.usage, now)
+			if !cmp.Equal(tt.want, names) {
+				diff := cmp.Diff(tt.want, names)
+				t.Errorf("state.SortFrecency() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// temporarySuffix separates the name of the target from the random part of its temporary files
+const temporarySuffix = ".tmp-"
+
+// WriteAtomic writes the data into a temporary file next to the target, syncs it to disk and
+// renames it to the target afterwards, so readers either see the old or the new content.
+// Symbolic links are resolved and the permissions of an existing target are preserved.
+func WriteAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, data, perm, true)
+}
+
+// WriteAtomicMode behaves like WriteAtomic, but always applies the given permissions, even to an existing target.
+// The temporary file gets the permissions before it replaces the target, so the content is never exposed.
+func WriteAtomicMode(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, data, perm, false)
+}
+
+func writeAtomic(path string, data []byte, perm os.FileMode, preserve bool) error {
+	target, err := filepath.EvalSymlinks(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		target = path
+	case err != nil:
+		return fmt.Errorf("could not resolve file '%s', err: '%w'", path, err)
+	}
+
+	if info, err := os.Stat(target); err == nil && preserve {
+		perm = info.Mode().Perm()
+	}
+
+	directory, name := filepath.Split(target)
+	tmpFile, err := os.CreateTemp(directory, "."+name+temporarySuffix+"*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file, err: '%w'", err)
+	}
+	// remove the temporary file, if anything goes wrong before the rename
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(data)
+	if err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("could not write temporary file, err: '%w'", err)
+	}
+	err = tmpFile.Chmod(perm)
+	if err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("could not set permissions of temporary file, err: '%w'", err)
+	}
+	err = tmpFile.Sync()
+	if err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("could not sync temporary file, err: '%w'", err)
+	}
+	err = tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file, err: '%w'", err)
+	}
+
+	err = os.Rename(tmpFile.Name(), target)
+	if err != nil {
+		return fmt.Errorf("could not replace file '%s', err: '%w'", target, err)
+	}
+
+	return syncDirectory(directory)
+}
+
+// IsTemporary reports, whether the file is a temporary file, that WriteAtomic creates while it replaces the target
+func IsTemporary(path string, target string) bool {
+	return strings.HasPrefix(filepath.Base(path), "."+filepath.Base(target)+temporarySuffix)
+}
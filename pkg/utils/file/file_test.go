@@ -0,0 +1,147 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_WriteAtomic(t *testing.T) {
+	type args struct {
+		data []byte
+		perm os.FileMode
+		// force applies the permissions to an existing file
+		force bool
+	}
+	tests := []struct {
+		name     string
+		before   func(directory string) (string, error)
+		args     args
+		wantPerm os.FileMode
+		wantErr  bool
+	}{
+		{
+			name: "should create a new file with the given permissions",
+			before: func(directory string) (string, error) {
+				return filepath.Join(directory, "kubeconfig.yaml"), nil
+			},
+			args: args{
+				data: []byte("kind: Config"),
+				perm: 0600,
+			},
+			wantPerm: 0600,
+		},
+		{
+			name: "should replace an existing file and preserve its permissions",
+			before: func(directory string) (string, error) {
+				path := filepath.Join(directory, "kubeconfig.yaml")
+				err := os.WriteFile(path, []byte("old content, that is longer than the new one"), 0640)
+				if err != nil {
+					return "", err
+				}
+				return path, os.Chmod(path, 0640)
+			},
+			args: args{
+				data: []byte("kind: Config"),
+				perm: 0600,
+			},
+			wantPerm: 0640,
+		},
+		{
+			name: "should replace an existing file and force the given permissions",
+			before: func(directory string) (string, error) {
+				path := filepath.Join(directory, "kubeconfig.yaml")
+				err := os.WriteFile(path, []byte("old"), 0644)
+				if err != nil {
+					return "", err
+				}
+				return path, os.Chmod(path, 0644)
+			},
+			args: args{
+				data:  []byte("kind: Config"),
+				perm:  0600,
+				force: true,
+			},
+			wantPerm: 0600,
+		},
+		{
+			name: "should replace the target of a symbolic link and keep the link",
+			before: func(directory string) (string, error) {
+				target := filepath.Join(directory, "target.yaml")
+				err := os.WriteFile(target, []byte("old"), 0600)
+				if err != nil {
+					return "", err
+				}
+				link := filepath.Join(directory, "kubeconfig.yaml")
+				return link, os.Symlink(target, link)
+			},
+			args: args{
+				data: []byte("kind: Config"),
+				perm: 0600,
+			},
+			wantPerm: 0600,
+		},
+		{
+			name: "should throw an error, as the directory does not exist",
+			before: func(directory string) (string, error) {
+				return filepath.Join(directory, "missing", "kubeconfig.yaml"), nil
+			},
+			args: args{
+				data: []byte("kind: Config"),
+				perm: 0600,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := t.TempDir()
+			path, err := tt.before(directory)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			if tt.args.force {
+				err = WriteAtomicMode(path, tt.args.data, tt.args.perm)
+			} else {
+				err = WriteAtomic(path, tt.args.data, tt.args.perm)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error, got: '%v'", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if string(got) != string(tt.args.data) {
+				t.Errorf("want: '%s', got: '%s'", tt.args.data, got)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if info.Mode().Perm() != tt.wantPerm {
+				t.Errorf("want: '%v', got: '%v'", tt.wantPerm, info.Mode().Perm())
+			}
+
+			// no temporary files must be left behind
+			entries, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			for _, entry := range entries {
+				if entry.Name() != "kubeconfig.yaml" && entry.Name() != "target.yaml" {
+					t.Errorf("unexpected file: '%s'", entry.Name())
+				}
+			}
+		})
+	}
+}
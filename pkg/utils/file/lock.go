@@ -0,0 +1,67 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const lockRetryInterval = 50 * time.Millisecond
+
+// ErrLocked is returned, if a lock could not be acquired within the given timeout
+var ErrLocked = errors.New("lock is held by another process")
+
+// Lock is an advisory, exclusive lock on a file, that is shared between processes
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes an exclusive lock on the given path and waits up to the given timeout,
+// if the lock is currently held by another process
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create lock directory, err: '%w'", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open lock file, err: '%w'", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("could not lock file '%s', err: '%w'", path, err)
+		}
+		if locked {
+			return &Lock{file: file}, nil
+		}
+		if time.Now().After(deadline) {
+			_ = file.Close()
+			return nil, fmt.Errorf("could not acquire lock '%s' within %s, err: '%w'", path, timeout, ErrLocked)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Release unlocks and closes the lock file
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	err := unlock(l.file)
+	if err != nil {
+		_ = l.file.Close()
+		return fmt.Errorf("could not release lock '%s', err: '%w'", l.file.Name(), err)
+	}
+
+	err = l.file.Close()
+	l.file = nil
+	return err
+}
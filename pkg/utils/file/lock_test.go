@@ -0,0 +1,38 @@
+package file
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_Acquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json.lock")
+
+	lock, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	// a second lock must time out, as long as the first one is held
+	_, err = Acquire(path, 100*time.Millisecond)
+	if !errors.Is(err, ErrLocked) {
+		t.Errorf("want: '%v', got: '%v'", ErrLocked, err)
+	}
+
+	err = lock.Release()
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	// the lock can be acquired again, after it has been released
+	lock, err = Acquire(path, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	err = lock.Release()
+	if err != nil {
+		t.Errorf("unexpected error, err: '%v'", err)
+	}
+}
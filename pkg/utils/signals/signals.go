@@ -0,0 +1,104 @@
+package signals
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/orbatschow/kontext/pkg/logger"
+)
+
+// Termination contains all signals, that ask kontext to stop, e.g. an interrupt, a kill or a closed terminal
+var Termination = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// Forwarder passes all termination signals to the running child processes, instead of stopping kontext right away.
+// Kontext has to remove its temporary files, e.g. kubeconfigs with credentials, once the child processes have exited.
+type Forwarder struct {
+	mutex     sync.Mutex
+	processes map[*os.Process]struct{}
+	received  os.Signal
+	channel   chan os.Signal
+}
+
+// Forward catches all termination signals until Stop is called and forwards them to the added processes
+func Forward() *Forwarder {
+	forwarder := &Forwarder{
+		processes: map[*os.Process]struct{}{},
+		channel:   make(chan os.Signal, 1),
+	}
+	signal.Notify(forwarder.channel, Termination...)
+
+	go func() {
+		for sig := range forwarder.channel {
+			forwarder.mutex.Lock()
+			forwarder.received = sig
+			for process := range forwarder.processes {
+				forwarder.signal(process, sig)
+			}
+			forwarder.mutex.Unlock()
+		}
+	}()
+
+	return forwarder
+}
+
+// Add forwards all further signals to the process, a signal, that has already been received, is sent right away
+func (f *Forwarder) Add(process *os.Process) {
+	if f == nil {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.processes[process] = struct{}{}
+	if f.received != nil {
+		f.signal(process, f.received)
+	}
+}
+
+// Remove stops forwarding signals to the process, it has to be called once the process has exited
+func (f *Forwarder) Remove(process *os.Process) {
+	if f == nil {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.processes, process)
+}
+
+// Received returns the last termination signal, that has been received, or nil
+func (f *Forwarder) Received() os.Signal {
+	if f == nil {
+		return nil
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.received
+}
+
+// Stop restores the default behaviour of all termination signals
+func (f *Forwarder) Stop() {
+	signal.Stop(f.channel)
+	close(f.channel)
+}
+
+func (f *Forwarder) signal(process *os.Process, sig os.Signal) {
+	log := logger.New().WithWriter(os.Stderr)
+
+	err := process.Signal(sig)
+	if err != nil {
+		log.Debug("could not forward signal", log.Args("signal", sig.String(), "pid", process.Pid, "error", err.Error()))
+	}
+}
+
+// ExitCode returns the exit code of the process, a process, that has been terminated by a signal,
+// exits with 128 plus the number of the signal, just like in a shell
+func ExitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
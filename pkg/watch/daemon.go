@@ -0,0 +1,46 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/orbatschow/kontext/pkg/config"
+)
+
+// LogFile returns the file, that receives the output of a watcher, which runs in the background
+func LogFile(currentConfig *config.Config) string {
+	return filepath.Join(filepath.Dir(currentConfig.State.File), "watch.log")
+}
+
+// Daemonize starts the current executable with the given arguments as detached background process
+// Its output is appended to the log file, the process id of the started process is returned.
+func Daemonize(args []string, logFile string) (int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("could not find executable, err: '%w'", err)
+	}
+
+	output, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("could not open log file, err: '%w'", err)
+	}
+	defer func() {
+		_ = output.Close()
+	}()
+
+	command := exec.Command(executable, args...)
+	command.Stdout = output
+	command.Stderr = output
+	command.SysProcAttr = detached()
+
+	err = command.Start()
+	if err != nil {
+		return 0, fmt.Errorf("could not start watcher, err: '%w'", err)
+	}
+	pid := command.Process.Pid
+	_ = command.Process.Release()
+
+	return pid, nil
+}
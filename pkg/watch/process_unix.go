@@ -0,0 +1,12 @@
+//go:build !windows
+
+package watch
+
+import (
+	"syscall"
+)
+
+// detached starts the process within a new session, so it survives the terminal, that started it
+func detached() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
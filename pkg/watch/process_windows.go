@@ -0,0 +1,17 @@
+//go:build windows
+
+package watch
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detached starts the process without a console, so it survives the terminal, that started it
+func detached() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+		HideWindow:    true,
+	}
+}
@@ -0,0 +1,288 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/fsnotify/fsnotify"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/group"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/logger"
+	"github.com/orbatschow/kontext/pkg/source"
+	"github.com/orbatschow/kontext/pkg/state"
+	"github.com/orbatschow/kontext/pkg/utils/file"
+	"github.com/samber/lo"
+)
+
+// DefaultDebounce is the duration without any further event, after which the active group is reloaded
+const DefaultDebounce = time.Second
+
+// Watcher reloads the active group, whenever a file of its sources changes
+type Watcher struct {
+	// File of the config file
+	File string
+	// Debounce collects bursts of events into a single reload
+	Debounce time.Duration
+	// Yes switches to protected contexts without confirmation
+	Yes bool
+
+	// reload is replaced by tests
+	reload func() error
+}
+
+// target contains everything, that is watched for the active group
+type target struct {
+	// Directories behind the include globs of all sources, the state directory is watched as well
+	Directories []string
+	// Patterns are the include globs of all sources
+	Patterns []string
+	// State is the state file, a changed state may activate another group
+	State string
+	// Kubeconfig is written by the reload itself and therefore ignored
+	Kubeconfig string
+}
+
+// Run watches the sources of the active group and reloads it, until the context is canceled
+// The watched directories are computed again after each reload and each change of the state.
+func (w *Watcher) Run(ctx context.Context) error {
+	log := logger.New()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	current, err := w.update(watcher, &target{})
+	if err != nil {
+		return err
+	}
+
+	debounce := lo.Ternary(w.Debounce > 0, w.Debounce, DefaultDebounce)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn("could not watch sources", log.Args("error", err.Error()))
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) == current.State {
+				log.Trace("state changed, computing watched directories", log.Args("file", event.Name))
+				current, err = w.update(watcher, current)
+				if err != nil {
+					log.Warn("could not compute watched directories", log.Args("error", err.Error()))
+				}
+				continue
+			}
+			if !current.relevant(event) {
+				continue
+			}
+			log.Debug("source changed", log.Args("file", event.Name, "operation", event.Op.String()))
+			timer.Reset(debounce)
+		case <-timer.C:
+			err := w.run()
+			if err != nil {
+				log.Error("could not reload group", log.Args("error", err.Error()))
+			}
+			current, err = w.update(watcher, current)
+			if err != nil {
+				log.Warn("could not compute watched directories", log.Args("error", err.Error()))
+			}
+		}
+	}
+}
+
+// run reloads the active group
+func (w *Watcher) run() error {
+	if w.reload != nil {
+		return w.reload()
+	}
+	return Reload(w.File, w.Yes)
+}
+
+// update replaces the watched directories with the directories of the active group
+func (w *Watcher) update(watcher *fsnotify.Watcher, previous *target) (*target, error) {
+	log := logger.New()
+
+	next, err := compute(w.File)
+	if err != nil {
+		return previous, err
+	}
+
+	for _, directory := range lo.Without(previous.Directories, next.Directories...) {
+		_ = watcher.Remove(directory)
+	}
+	for _, directory := range lo.Without(next.Directories, previous.Directories...) {
+		err := watcher.Add(directory)
+		if err != nil {
+			log.Warn("could not watch directory", log.Args("directory", directory, "error", err.Error()))
+			continue
+		}
+		log.Debug("watching directory", log.Args("directory", directory))
+	}
+
+	return next, nil
+}
+
+// relevant reports, whether the event changes a file of the sources or adds a directory, that may contain such files
+func (t *target) relevant(event fsnotify.Event) bool {
+	name := filepath.Clean(event.Name)
+	if name == t.Kubeconfig || event.Op == fsnotify.Chmod {
+		return false
+	}
+	// the reload replaces the kubeconfig and the state through temporary files next to them
+	if file.IsTemporary(name, t.Kubeconfig) || file.IsTemporary(name, t.State) {
+		return false
+	}
+	if event.Op&fsnotify.Create != 0 {
+		if info, err := os.Stat(name); err == nil && info.IsDir() {
+			return true
+		}
+	}
+
+	return lo.ContainsBy(t.Patterns, func(pattern string) bool {
+		match, err := doublestar.PathMatch(pattern, name)
+		return err == nil && match
+	})
+}
+
+// compute reads the config and state and returns the target of the active group
+func compute(configFile string) (*target, error) {
+	configClient := &config.Client{
+		File: configFile,
+	}
+	currentConfig, err := configClient.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &target{
+		Directories: []string{filepath.Dir(filepath.Clean(currentConfig.State.File))},
+		State:       filepath.Clean(currentConfig.State.File),
+		Kubeconfig:  filepath.Clean(currentConfig.Global.Kubeconfig),
+	}
+
+	currentState, err := state.Read(currentConfig)
+	if err != nil {
+		return nil, err
+	}
+	activeGroup, ok := lo.Find(currentConfig.Group.Items, func(item config.GroupItem) bool {
+		return item.Name == currentState.Group.Active
+	})
+	if !ok {
+		return buffer, nil
+	}
+
+	sources, err := source.GroupSources(currentConfig, &activeGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range currentConfig.Source.Items {
+		if !lo.Contains(sources, item.Name) {
+			continue
+		}
+		for _, include := range item.Include {
+			pattern := filepath.Clean(include)
+			buffer.Patterns = append(buffer.Patterns, pattern)
+
+			directories, err := directories(pattern)
+			if err != nil {
+				return nil, err
+			}
+			buffer.Directories = append(buffer.Directories, directories...)
+		}
+	}
+
+	buffer.Directories = lo.Uniq(buffer.Directories)
+	sort.Strings(buffer.Directories)
+	return buffer, nil
+}
+
+// directories returns the static base directory of the glob, patterns, that match files within subdirectories,
+// e.g. dir/*/*.yaml or dir/**, return all existing subdirectories of the base directory as well
+func directories(pattern string) ([]string, error) {
+	base, rest := doublestar.SplitPattern(filepath.ToSlash(pattern))
+	base = filepath.FromSlash(base)
+	if !strings.Contains(rest, "/") && !strings.Contains(rest, "**") {
+		return []string{base}, nil
+	}
+
+	var buffer []string
+	err := filepath.WalkDir(base, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// directories, that do not exist yet, can not be watched
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			buffer = append(buffer, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buffer, nil
+}
+
+// Reload reloads the active group while holding the state lock
+func Reload(configFile string, yes bool) error {
+	configClient := &config.Client{
+		File: configFile,
+	}
+	currentConfig, err := configClient.Read()
+	if err != nil {
+		return err
+	}
+	lock, err := state.Lock(currentConfig)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lock.Release()
+	}()
+
+	client, err := group.New(configFile)
+	if err != nil {
+		return err
+	}
+	client.Yes = yes
+
+	err = client.Reload()
+	if err != nil {
+		return err
+	}
+
+	err = kubeconfig.WriteFile(client.Config.Global.Kubeconfig, client.APIConfig)
+	if err != nil {
+		return err
+	}
+	err = state.Write(client.Config, client.State)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/go-cmp/cmp"
+	"github.com/orbatschow/kontext/pkg/config"
+	"github.com/orbatschow/kontext/pkg/kubeconfig"
+	"github.com/orbatschow/kontext/pkg/state"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// setup writes a config with a single group, whose source includes all yaml files within the sources directory
+func setup(t *testing.T, currentContext string) (string, string) {
+	t.Helper()
+	_, caller, _, _ := runtime.Caller(0)
+	directory := t.TempDir()
+
+	sources := filepath.Join(directory, "sources")
+	err := os.MkdirAll(sources, 0755)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	data, err := os.ReadFile(filepath.Join(caller, "..", "testdata", "01-valid-kubeconfig.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	err = os.WriteFile(filepath.Join(sources, "dev.yaml"), data, 0600)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	configFile := filepath.Join(directory, "kontext.yaml")
+	err = os.WriteFile(configFile, []byte(fmt.Sprintf(`global:
+  kubeconfig: %[1]s/kubeconfig.yaml
+state:
+  file: %[1]s/state/state.json
+backup:
+  enabled: false
+group:
+  items:
+    - name: dev
+      context:
+        default: kind-dev
+      sources:
+        - dev
+source:
+  items:
+    - name: dev
+      include:
+        - %[1]s/sources/*.yaml
+`, filepath.ToSlash(directory))), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	configClient := &config.Client{File: configFile}
+	currentConfig, err := configClient.Read()
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	err = state.Init(currentConfig)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	err = state.Write(currentConfig, &state.State{
+		Group:   state.Group{Active: "dev"},
+		Context: state.Context{Active: currentContext},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	apiConfig, err := clientcmd.Load(data)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+	apiConfig.CurrentContext = currentContext
+	if context, ok := apiConfig.Contexts[currentContext]; ok {
+		context.Namespace = "monitoring"
+	}
+	err = kubeconfig.WriteFile(currentConfig.Global.Kubeconfig, apiConfig)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	return configFile, sources
+}
+
+func Test_directories(t *testing.T) {
+	directory := t.TempDir()
+	for _, path := range []string{"a/b", "c"} {
+		err := os.MkdirAll(filepath.Join(directory, path), 0755)
+		if err != nil {
+			t.Fatalf("unexpected error, err: '%v'", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "should only return the base directory, as the pattern does not match subdirectories",
+			pattern: filepath.Join(directory, "*.yaml"),
+			want:    []string{directory},
+		},
+		{
+			name:    "should return the base directory and all subdirectories",
+			pattern: filepath.Join(directory, "**", "*.yaml"),
+			want: []string{
+				directory,
+				filepath.Join(directory, "a"),
+				filepath.Join(directory, "a", "b"),
+				filepath.Join(directory, "c"),
+			},
+		},
+		{
+			name:    "should return the base directory and all subdirectories, as the pattern ends with a double star",
+			pattern: filepath.Join(directory, "**"),
+			want: []string{
+				directory,
+				filepath.Join(directory, "a"),
+				filepath.Join(directory, "a", "b"),
+				filepath.Join(directory, "c"),
+			},
+		},
+		{
+			name:    "should return nothing, as the base directory does not exist",
+			pattern: filepath.Join(directory, "missing", "*", "*.yaml"),
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := directories(tt.pattern)
+			if err != nil {
+				t.Errorf("unexpected error, err: '%v'", err)
+			}
+			if !cmp.Equal(tt.want, got) {
+				diff := cmp.Diff(tt.want, got)
+				t.Errorf("watch.directories() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_relevant(t *testing.T) {
+	directory := t.TempDir()
+	nested := t.TempDir()
+	current := &target{
+		Patterns:   []string{filepath.Join(directory, "*"), filepath.Join(nested, "**")},
+		State:      filepath.Join(directory, "state.json"),
+		Kubeconfig: filepath.Join(directory, "kubeconfig.yaml"),
+	}
+
+	tests := []struct {
+		name  string
+		event fsnotify.Event
+		want  bool
+	}{
+		{
+			name:  "should be relevant, as the file matches an include glob",
+			event: fsnotify.Event{Name: filepath.Join(directory, "dev.yaml"), Op: fsnotify.Write},
+			want:  true,
+		},
+		{
+			name:  "should be relevant, as a matching file has been removed",
+			event: fsnotify.Event{Name: filepath.Join(directory, "dev.yaml"), Op: fsnotify.Remove},
+			want:  true,
+		},
+		{
+			name:  "should be relevant, as the nested file matches a double star glob",
+			event: fsnotify.Event{Name: filepath.Join(nested, "a", "b", "dev.yaml"), Op: fsnotify.Create},
+			want:  true,
+		},
+		{
+			name:  "should be relevant, as a directory has been created",
+			event: fsnotify.Event{Name: directory, Op: fsnotify.Create},
+			want:  true,
+		},
+		{
+			name:  "should not be relevant, as the file does not match any include glob",
+			event: fsnotify.Event{Name: filepath.Join(t.TempDir(), "dev.yaml"), Op: fsnotify.Write},
+			want:  false,
+		},
+		{
+			name:  "should not be relevant, as only the permissions changed",
+			event: fsnotify.Event{Name: filepath.Join(directory, "dev.yaml"), Op: fsnotify.Chmod},
+			want:  false,
+		},
+		{
+			name:  "should not be relevant, as the kubeconfig is written by the reload",
+			event: fsnotify.Event{Name: filepath.Join(directory, "kubeconfig.yaml"), Op: fsnotify.Write},
+			want:  false,
+		},
+		{
+			name:  "should not be relevant, as the temporary kubeconfig is written by the reload",
+			event: fsnotify.Event{Name: filepath.Join(directory, ".kubeconfig.yaml.tmp-1234"), Op: fsnotify.Create},
+			want:  false,
+		},
+		{
+			name:  "should not be relevant, as the temporary state is written by the reload",
+			event: fsnotify.Event{Name: filepath.Join(directory, ".state.json.tmp-1234"), Op: fsnotify.Rename},
+			want:  false,
+		},
+		{
+			name:  "should be relevant, as the temporary file belongs to another file",
+			event: fsnotify.Event{Name: filepath.Join(directory, ".dev.yaml.tmp-1234"), Op: fsnotify.Create},
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := current.relevant(tt.event)
+			if got != tt.want {
+				t.Errorf("want '%v', got: '%v'", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Run(t *testing.T) {
+	configFile, sources := setup(t, "kind-dev")
+
+	var reloads atomic.Int32
+	watcher := &Watcher{
+		File:     configFile,
+		Debounce: 100 * time.Millisecond,
+		reload: func() error {
+			reloads.Add(1)
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watcher.Run(ctx)
+	}()
+	// give the watcher time to add the directories
+	time.Sleep(200 * time.Millisecond)
+
+	// a burst of changes results in a single reload
+	for i := 0; i < 3; i++ {
+		err := os.WriteFile(filepath.Join(sources, fmt.Sprintf("%d.yaml", i)), []byte("kind: Config\n"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error, err: '%v'", err)
+		}
+	}
+	err := os.WriteFile(filepath.Join(sources, "notes.txt"), []byte("ignored"), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error, err: '%v'", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for reloads.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	cancel()
+	err = <-done
+	if err != nil {
+		t.Errorf("unexpected error, err: '%v'", err)
+	}
+	if got := reloads.Load(); got != 1 {
+		t.Errorf("want '1' reload, got: '%d'", got)
+	}
+}
+
+func Test_Reload(t *testing.T) {
+	tests := []struct {
+		name          string
+		context       string
+		wantContext   string
+		wantNamespace string
+	}{
+		{
+			name:          "should keep the active context and its namespace, as the group still provides it",
+			context:       "kind-prod",
+			wantContext:   "kind-prod",
+			wantNamespace: "monitoring",
+		},
+		{
+			name:        "should switch to the default context, as the group no longer provides the active context",
+			context:     "kind-removed",
+			wantContext: "kind-dev",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile, _ := setup(t, tt.context)
+
+			err := Reload(configFile, false)
+			if err != nil {
+				t.Fatalf("unexpected error, err: '%v'", err)
+			}
+
+			configClient := &config.Client{File: configFile}
+			currentConfig, err := configClient.Read()
+			if err != nil {
+				t.Fatalf("unexpected error, err: '%v'", err)
+			}
+			apiConfig, err := kubeconfig.ReadFile(currentConfig.Global.Kubeconfig)
+			if err != nil {
+				t.Fatalf("unexpected error, err: '%v'", err)
+			}
+			currentState, err := state.Read(currentConfig)
+			if err != nil {
+				t.Fatalf("unexpected error, err: '%v'", err)
+			}
+
+			if apiConfig.CurrentContext != tt.wantContext {
+				t.Errorf("want context '%s', got: '%s'", tt.wantContext, apiConfig.CurrentContext)
+			}
+			if currentState.Context.Active != tt.wantContext {
+				t.Errorf("want active context '%s', got: '%s'", tt.wantContext, currentState.Context.Active)
+			}
+			if namespace := apiConfig.Contexts[tt.wantContext].Namespace; namespace != tt.wantNamespace {
+				t.Errorf("want namespace '%s', got: '%s'", tt.wantNamespace, namespace)
+			}
+		})
+	}
+}